@@ -0,0 +1,147 @@
+// Command cloudflare-exporter exposes the analytics of Cloudflare zones as
+// Prometheus metrics.
+//
+// Every interval it fetches the zone analytics dashboard, and the analytics
+// by colocation where the zone's plan allows it, for the last window of time.
+// The metrics are gauges holding the totals over that window, served in the
+// Prometheus text format at /metrics.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudflare/cloudflare-go"
+)
+
+// exporter fetches analytics for a list of zones and serves the metrics of
+// the last scrape.
+type exporter struct {
+	api    *cloudflare.API
+	zones  []string
+	window time.Duration
+
+	mu      sync.RWMutex
+	metrics []byte
+}
+
+// scrape fetches the analytics of the zones and replaces the metrics served.
+func (e *exporter) scrape() {
+	start := time.Now()
+	metrics := make(metricSet)
+	var errs int
+
+	// With no names given, ListZones pages through every zone in the
+	// account.
+	zones, err := e.api.ListZones(e.zones...)
+	if err != nil {
+		log.Println("Error listing zones:", err)
+		errs++
+	}
+	for _, z := range zones {
+		opts := cloudflare.ZoneAnalyticsLast(e.window)
+		continuous := true
+		opts.Continuous = &continuous
+
+		data, err := e.api.ZoneAnalyticsDashboard(z.ID, opts)
+		if err != nil {
+			log.Printf("Error fetching analytics for %s: %s", z.Name, err)
+			errs++
+			continue
+		}
+		addZoneMetrics(metrics, z.Name, data.Totals)
+
+		// Analytics by colocation are only available on some plans, so
+		// failures aren't counted as errors.
+		colos, err := e.api.ZoneAnalyticsByColocation(z.ID, opts)
+		if err != nil {
+			continue
+		}
+		for _, c := range colos {
+			addColoMetrics(metrics, z.Name, c.ColocationID, cloudflare.SumZoneAnalytics(c.Timeseries))
+		}
+	}
+
+	metrics.add("cloudflare_exporter_scrape_errors", "Number of errors in the last scrape.", float64(errs))
+	metrics.add("cloudflare_exporter_scrape_duration_seconds", "Duration of the last scrape.", time.Since(start).Seconds())
+	metrics.add("cloudflare_exporter_last_scrape_timestamp_seconds", "Time of the last scrape, in seconds since the epoch.", float64(start.Unix()))
+
+	b := metrics.bytes()
+	e.mu.Lock()
+	e.metrics = b
+	e.mu.Unlock()
+}
+
+// addZoneMetrics records the totals of a zone.
+func addZoneMetrics(m metricSet, zone string, a cloudflare.ZoneAnalytics) {
+	m.add("cloudflare_zone_requests", "Requests served over the window.", float64(a.Requests.All), "zone", zone)
+	m.add("cloudflare_zone_requests_cached", "Requests served from cache over the window.", float64(a.Requests.Cached), "zone", zone)
+	m.add("cloudflare_zone_requests_uncached", "Requests served from the origin over the window.", float64(a.Requests.Uncached), "zone", zone)
+	m.addCounts("cloudflare_zone_requests_by_status", "Requests served over the window by HTTP status.", a.Requests.HTTPStatus, "status", "zone", zone)
+	m.addCounts("cloudflare_zone_requests_by_country", "Requests served over the window by country.", a.Requests.Country, "country", "zone", zone)
+	m.add("cloudflare_zone_bandwidth_bytes", "Bytes served over the window.", float64(a.Bandwidth.All), "zone", zone)
+	m.add("cloudflare_zone_bandwidth_cached_bytes", "Bytes served from cache over the window.", float64(a.Bandwidth.Cached), "zone", zone)
+	m.add("cloudflare_zone_bandwidth_uncached_bytes", "Bytes served from the origin over the window.", float64(a.Bandwidth.Uncached), "zone", zone)
+	m.add("cloudflare_zone_threats", "Threats seen over the window.", float64(a.Threats.All), "zone", zone)
+	m.addCounts("cloudflare_zone_threats_by_type", "Threats seen over the window by type.", a.Threats.Type, "type", "zone", zone)
+	m.addCounts("cloudflare_zone_threats_by_country", "Threats seen over the window by country.", a.Threats.Country, "country", "zone", zone)
+	m.add("cloudflare_zone_pageviews", "Pageviews over the window.", float64(a.Pageviews.All), "zone", zone)
+	m.add("cloudflare_zone_uniques", "Unique visitors over the window.", float64(a.Uniques.All), "zone", zone)
+}
+
+// addColoMetrics records the totals of a zone at one colocation.
+func addColoMetrics(m metricSet, zone, colo string, a cloudflare.ZoneAnalytics) {
+	m.add("cloudflare_colo_requests", "Requests served over the window by colocation.", float64(a.Requests.All), "zone", zone, "colo", colo)
+	m.add("cloudflare_colo_requests_cached", "Requests served from cache over the window by colocation.", float64(a.Requests.Cached), "zone", zone, "colo", colo)
+	m.add("cloudflare_colo_bandwidth_bytes", "Bytes served over the window by colocation.", float64(a.Bandwidth.All), "zone", zone, "colo", colo)
+	m.add("cloudflare_colo_bandwidth_cached_bytes", "Bytes served from cache over the window by colocation.", float64(a.Bandwidth.Cached), "zone", zone, "colo", colo)
+	m.add("cloudflare_colo_threats", "Threats seen over the window by colocation.", float64(a.Threats.All), "zone", zone, "colo", colo)
+}
+
+func (e *exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.RLock()
+	b := e.metrics
+	e.mu.RUnlock()
+	if b == nil {
+		http.Error(w, "no scrape has completed yet", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(b)
+}
+
+func main() {
+	listen := flag.String("listen", ":9199", "address to serve metrics on")
+	zones := flag.String("zones", "", "comma-separated zone names (default all zones)")
+	interval := flag.Duration("interval", 5*time.Minute, "time between scrapes of the API")
+	window := flag.Duration("window", 30*time.Minute, "period of time the metrics cover")
+	flag.Parse()
+
+	api, err := cloudflare.New(os.Getenv("CF_API_KEY"), os.Getenv("CF_API_EMAIL"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	e := &exporter{api: api, window: *window}
+	if *zones != "" {
+		e.zones = strings.Split(*zones, ",")
+	}
+
+	go func() {
+		for {
+			e.scrape()
+			time.Sleep(*interval)
+		}
+	}()
+
+	http.Handle("/metrics", e)
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `<html><body><a href="/metrics">Metrics</a></body></html>`)
+	})
+	log.Fatal(http.ListenAndServe(*listen, nil))
+}
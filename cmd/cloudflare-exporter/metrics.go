@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// metric is a gauge with its samples, in the Prometheus text exposition
+// format.
+type metric struct {
+	name    string
+	help    string
+	samples []sample
+}
+
+type sample struct {
+	labels []string // alternating names and values
+	value  float64
+}
+
+// metricSet collects the metrics of one scrape.
+type metricSet map[string]*metric
+
+// add records a sample of the gauge name. labels holds pairs of label names
+// and values.
+func (s metricSet) add(name, help string, value float64, labels ...string) {
+	m, ok := s[name]
+	if !ok {
+		m = &metric{name: name, help: help}
+		s[name] = m
+	}
+	m.samples = append(m.samples, sample{labels: labels, value: value})
+}
+
+// addCounts records a sample for each entry of counts, labelled with label.
+func (s metricSet) addCounts(name, help string, counts map[string]int, label string, labels ...string) {
+	for k, n := range counts {
+		l := append(append([]string{}, labels...), label, k)
+		s.add(name, help, float64(n), l...)
+	}
+}
+
+// bytes renders the metrics in the text exposition format, version 0.0.4,
+// ordered by name so that the output is stable.
+func (s metricSet) bytes() []byte {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		m := s[name]
+		fmt.Fprintf(&buf, "# HELP %s %s\n", m.name, escapeHelp(m.help))
+		fmt.Fprintf(&buf, "# TYPE %s gauge\n", m.name)
+		lines := make([]string, len(m.samples))
+		for i, smp := range m.samples {
+			lines[i] = m.name + formatLabels(smp.labels) + " " + formatValue(smp.value)
+		}
+		sort.Strings(lines)
+		for _, line := range lines {
+			buf.WriteString(line)
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes()
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func formatLabels(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, labels[i]+`="`+labelEscaper.Replace(labels[i+1])+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricSetBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		add  func(metricSet)
+		want string
+	}{
+		{
+			name: "empty",
+			add:  func(metricSet) {},
+			want: "",
+		},
+		{
+			name: "no labels",
+			add: func(m metricSet) {
+				m.add("up", "Whether the last scrape worked.", 1)
+			},
+			want: "# HELP up Whether the last scrape worked.\n" +
+				"# TYPE up gauge\n" +
+				"up 1\n",
+		},
+		{
+			name: "escaping",
+			add: func(m metricSet) {
+				m.add("requests", "Help with a \\ and a\nnewline.", 3, "zone", "a\"b\\c\nd")
+			},
+			want: "# HELP requests Help with a \\\\ and a\\nnewline.\n" +
+				"# TYPE requests gauge\n" +
+				"requests{zone=\"a\\\"b\\\\c\\nd\"} 3\n",
+		},
+		{
+			name: "special values",
+			add: func(m metricSet) {
+				m.add("v", "Values.", math.NaN(), "k", "a")
+				m.add("v", "Values.", math.Inf(1), "k", "b")
+				m.add("v", "Values.", math.Inf(-1), "k", "c")
+				m.add("v", "Values.", 0.25, "k", "d")
+				m.add("v", "Values.", 1e21, "k", "e")
+			},
+			want: "# HELP v Values.\n" +
+				"# TYPE v gauge\n" +
+				"v{k=\"a\"} NaN\n" +
+				"v{k=\"b\"} +Inf\n" +
+				"v{k=\"c\"} -Inf\n" +
+				"v{k=\"d\"} 0.25\n" +
+				"v{k=\"e\"} 1e+21\n",
+		},
+		{
+			name: "ordering",
+			add: func(m metricSet) {
+				m.add("b", "B.", 2, "zone", "y")
+				m.add("a", "A.", 1)
+				m.add("b", "B.", 1, "zone", "x")
+			},
+			want: "# HELP a A.\n" +
+				"# TYPE a gauge\n" +
+				"a 1\n" +
+				"# HELP b B.\n" +
+				"# TYPE b gauge\n" +
+				"b{zone=\"x\"} 1\n" +
+				"b{zone=\"y\"} 2\n",
+		},
+		{
+			name: "counts",
+			add: func(m metricSet) {
+				// The shared labels must be copied for every sample.
+				labels := make([]string, 2, 16)
+				labels[0], labels[1] = "zone", "example.com"
+				m.addCounts("status", "By status.", map[string]int{"200": 5, "404": 1, "500": 2}, "status", labels...)
+			},
+			want: "# HELP status By status.\n" +
+				"# TYPE status gauge\n" +
+				"status{zone=\"example.com\",status=\"200\"} 5\n" +
+				"status{zone=\"example.com\",status=\"404\"} 1\n" +
+				"status{zone=\"example.com\",status=\"500\"} 2\n",
+		},
+	}
+	for _, tt := range tests {
+		m := make(metricSet)
+		tt.add(m)
+		assert.Equal(t, tt.want, string(m.bytes()), tt.name)
+	}
+}
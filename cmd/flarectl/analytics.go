@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cloudflare/cloudflare-go"
+	"github.com/codegangsta/cli"
+)
+
+// sparklineWidth is the largest number of points drawn in a sparkline; longer
+// timeseries are resampled to fit.
+const sparklineWidth = 60
+
+// minWatchInterval is the shortest time allowed between refreshes with
+// --watch, to stay well within the API's rate limits.
+const minWatchInterval = 10 * time.Second
+
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws values as a line of block characters scaled between their
+// minimum and maximum.
+func sparkline(values []int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	min, max := values[0], values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	line := make([]rune, len(values))
+	for i, v := range values {
+		tick := 0
+		if max > min {
+			tick = (v - min) * (len(sparkTicks) - 1) / (max - min)
+		}
+		line[i] = sparkTicks[tick]
+	}
+	return string(line)
+}
+
+// formatCount abbreviates large numbers, such as 1.2M.
+func formatCount(n int) string {
+	switch {
+	case n >= 1e9:
+		return fmt.Sprintf("%.1fG", float64(n)/1e9)
+	case n >= 1e6:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1e4:
+		return fmt.Sprintf("%.1fk", float64(n)/1e3)
+	}
+	return strconv.Itoa(n)
+}
+
+// formatBytes formats a number of bytes with a binary unit, such as 1.5 MiB.
+func formatBytes(n int) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := unit, 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// printTopCounts prints the n largest counts with their share of total.
+func printTopCounts(title string, counts map[string]int, total, n int) {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+
+	fmt.Println(title)
+	for _, k := range keys {
+		var share float64
+		if total > 0 {
+			share = 100 * float64(counts[k]) / float64(total)
+		}
+		fmt.Printf("  %-6s %8s %5.1f%%\n", k, formatCount(counts[k]), share)
+	}
+}
+
+// printZoneAnalytics renders the analytics dashboard of a zone.
+func printZoneAnalytics(zone string, data cloudflare.ZoneAnalyticsData, top int) {
+	t := data.Totals
+	fmt.Printf("%s: %s to %s\n\n", zone, t.Since.Format(time.RFC3339), t.Until.Format(time.RFC3339))
+
+	makeTable([]table{
+		{"Metric": "Requests", "All": formatCount(t.Requests.All), "Cached": formatCount(t.Requests.Cached), "Uncached": formatCount(t.Requests.Uncached)},
+		{"Metric": "Bandwidth", "All": formatBytes(t.Bandwidth.All), "Cached": formatBytes(t.Bandwidth.Cached), "Uncached": formatBytes(t.Bandwidth.Uncached)},
+		{"Metric": "Threats", "All": formatCount(t.Threats.All)},
+		{"Metric": "Pageviews", "All": formatCount(t.Pageviews.All)},
+		{"Metric": "Uniques", "All": formatCount(t.Uniques.All)},
+	}, "Metric", "All", "Cached", "Uncached")
+	fmt.Printf("\nCache hit ratio %.1f%%, bandwidth saved %.1f%% (%s)\n\n",
+		100*t.CacheHitRatio(), 100*t.BandwidthSaved(), formatBytes(t.Bandwidth.Cached))
+
+	series := data.Timeseries
+	if n := len(series); n > sparklineWidth {
+		span := series[n-1].Until.Sub(series[0].Since)
+		interval := (span/sparklineWidth + time.Minute).Truncate(time.Minute)
+		series = cloudflare.ResampleZoneAnalytics(series, interval)
+	}
+	var requests, bandwidth, threats []int
+	for _, a := range series {
+		requests = append(requests, a.Requests.All)
+		bandwidth = append(bandwidth, a.Bandwidth.All)
+		threats = append(threats, a.Threats.All)
+	}
+	fmt.Printf("%-10s %s\n", "Requests", sparkline(requests))
+	fmt.Printf("%-10s %s\n", "Bandwidth", sparkline(bandwidth))
+	fmt.Printf("%-10s %s\n\n", "Threats", sparkline(threats))
+
+	printTopCounts("Top countries", t.Requests.Country, t.Requests.All, top)
+	fmt.Println()
+	printTopCounts("Top status codes", t.Requests.HTTPStatus, t.Requests.All, top)
+}
+
+func zoneAnalytics(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone"); err != nil {
+		return
+	}
+	zone := c.String("zone")
+
+	interval := c.Duration("interval")
+	if c.Bool("watch") && interval < minWatchInterval {
+		fmt.Printf("--interval must be at least %s\n", minWatchInterval)
+		return
+	}
+
+	zoneID, err := api.ZoneIDByName(zone)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for {
+		// Relative times are worked out again on every refresh.
+		var opts cloudflare.ZoneAnalyticsOptions
+		since, err := parseSince(c.String("since"))
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		opts.Since = &since
+		if c.String("until") != "" {
+			until, err := parseSince(c.String("until"))
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			opts.Until = &until
+		}
+
+		data, err := api.ZoneAnalyticsDashboard(zoneID, opts)
+		if !c.Bool("watch") {
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			printZoneAnalytics(zone, data, c.Int("top"))
+			return
+		}
+
+		// Clear the screen before each refresh.
+		fmt.Print("\033[H\033[2J")
+		if err != nil {
+			fmt.Println(err)
+		} else {
+			printZoneAnalytics(zone, data, c.Int("top"))
+		}
+		fmt.Printf("\nUpdated %s, refreshing every %s\n", time.Now().Format("15:04:05"), interval)
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []int
+		want   string
+	}{
+		{nil, ""},
+		{[]int{5}, "▁"},
+		{[]int{3, 3, 3}, "▁▁▁"},
+		{[]int{0, 7}, "▁█"},
+		{[]int{0, 1, 2, 3, 4, 5, 6, 7}, "▁▂▃▄▅▆▇█"},
+		{[]int{10, 0, 5}, "█▁▄"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, sparkline(tt.values), "%v", tt.values)
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "0"},
+		{9999, "9999"},
+		{10000, "10.0k"},
+		{123456, "123.5k"},
+		{1500000, "1.5M"},
+		{2000000000, "2.0G"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, formatCount(tt.n), "%d", tt.n)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{5 * 1024 * 1024, "5.0 MiB"},
+		{3 << 30, "3.0 GiB"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, formatBytes(tt.n), "%d", tt.n)
+	}
+}
@@ -161,3 +161,83 @@ func dnsDelete(c *cli.Context) {
 		fmt.Println("Error deleting DNS record:", err)
 	}
 }
+
+func dnsAXFRImport(c *cli.Context) {
+	if err := checkFlags(c, "zone", "nameserver"); err != nil {
+		return
+	}
+	zone := c.String("zone")
+
+	records, skipped, err := cloudflare.ZoneTransfer(zone, c.String("nameserver"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, s := range skipped {
+		fmt.Println("Skipping:", s)
+	}
+
+	if c.Bool("dry-run") {
+		var output []table
+		for _, r := range records {
+			output = append(output, table{
+				"Type":    r.Type,
+				"Name":    r.Name,
+				"Content": recordContent(r),
+				"TTL":     fmt.Sprintf("%d", r.TTL),
+			})
+		}
+		makeTable(output, "Type", "Name", "Content", "TTL")
+		return
+	}
+
+	// A dry run doesn't use the API, so credentials are only needed here.
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	zoneID, err := api.ZoneIDByName(zone)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	results, err := api.CreateDNSRecords(zoneID, records, cloudflare.DNSBatchOptions{
+		Concurrency: c.Int("concurrency"),
+	})
+	var output []table
+	for _, r := range results {
+		var status string
+		if r.Err != nil {
+			status = r.Err.Error()
+		}
+		output = append(output, table{
+			"ID":      r.ID,
+			"Type":    r.Record.Type,
+			"Name":    r.Record.Name,
+			"Content": recordContent(r.Record),
+			"Error":   status,
+		})
+	}
+	makeTable(output, "ID", "Type", "Name", "Content", "Error")
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
+// recordContent formats the content of a record for display, including the
+// fields that are held separately for MX, SRV and CAA records.
+func recordContent(r cloudflare.DNSRecord) string {
+	switch r.Type {
+	case "MX":
+		return fmt.Sprintf("%d %s", r.Priority, r.Content)
+	case "SRV", "CAA":
+		if d, ok := r.Data.(map[string]interface{}); ok {
+			if r.Type == "SRV" {
+				return fmt.Sprintf("%v %v %v %v", d["priority"], d["weight"], d["port"], d["target"])
+			}
+			return fmt.Sprintf("%v %v %q", d["flags"], d["tag"], d["value"])
+		}
+	}
+	return r.Content
+}
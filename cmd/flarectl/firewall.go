@@ -0,0 +1,314 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/cloudflare-go"
+	"github.com/codegangsta/cli"
+)
+
+// accessRuleTarget guesses the access rule target of value: an IP address, a
+// CIDR range, an AS number such as AS13335 or a two-letter country code.
+func accessRuleTarget(value string) (string, error) {
+	if _, _, err := net.ParseCIDR(value); err == nil {
+		return "ip_range", nil
+	}
+	if net.ParseIP(value) != nil {
+		return "ip", nil
+	}
+	upper := strings.ToUpper(value)
+	if strings.HasPrefix(upper, "AS") && len(upper) > 2 && strings.Trim(upper[2:], "0123456789") == "" {
+		return "asn", nil
+	}
+	if len(value) == 2 && strings.Trim(upper, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") == "" {
+		return "country", nil
+	}
+	return "", fmt.Errorf("%q is not an IP address, IP range, ASN or country code", value)
+}
+
+// accessRuleScope returns the ID of the zone given with --zone, or of the
+// organization given with --org-id. If neither is given both are empty and
+// the rule applies to the user.
+func accessRuleScope(c *cli.Context) (zoneID, orgID string, err error) {
+	if zone := c.String("zone"); zone != "" {
+		zoneID, err = api.ZoneIDByName(zone)
+		return zoneID, "", err
+	}
+	return "", c.String("org-id"), nil
+}
+
+func firewallAccessRules(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	zoneID, orgID, err := accessRuleScope(c)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	filter := cloudflare.AccessRule{Mode: c.String("mode")}
+	filter.Configuration.Value = c.String("value")
+	var rules []cloudflare.AccessRule
+	switch {
+	case zoneID != "":
+		rules, err = api.ListZoneAccessRules(zoneID, filter)
+	case orgID != "":
+		rules, err = api.ListOrganizationAccessRules(orgID, filter)
+	default:
+		rules, err = api.ListUserAccessRules(filter)
+	}
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	var output []table
+	for _, r := range rules {
+		var scope string
+		if r.Scope != nil {
+			scope = r.Scope.Type
+		}
+		output = append(output, table{
+			"ID":     r.ID,
+			"Mode":   r.Mode,
+			"Target": r.Configuration.Target,
+			"Value":  r.Configuration.Value,
+			"Scope":  scope,
+			"Notes":  r.Notes,
+		})
+	}
+	makeTable(output, "ID", "Mode", "Target", "Value", "Scope", "Notes")
+}
+
+func firewallBlock(c *cli.Context) {
+	firewallCreateAccessRule(c, cloudflare.AccessRuleBlock)
+}
+
+func firewallChallenge(c *cli.Context) {
+	firewallCreateAccessRule(c, cloudflare.AccessRuleChallenge)
+}
+
+func firewallAllow(c *cli.Context) {
+	firewallCreateAccessRule(c, cloudflare.AccessRuleWhitelist)
+}
+
+func firewallCreateAccessRule(c *cli.Context, mode string) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	value := c.String("value")
+	if value == "" {
+		value = c.Args().First()
+	}
+	if value == "" {
+		cli.ShowSubcommandHelp(c)
+		return
+	}
+	target, err := accessRuleTarget(value)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	zoneID, orgID, err := accessRuleScope(c)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	rule := cloudflare.AccessRule{Mode: mode, Notes: c.String("notes")}
+	rule.Configuration.Target = target
+	rule.Configuration.Value = value
+	switch {
+	case zoneID != "":
+		rule, err = api.CreateZoneAccessRule(zoneID, rule)
+	case orgID != "":
+		rule, err = api.CreateOrganizationAccessRule(orgID, rule)
+	default:
+		rule, err = api.CreateUserAccessRule(rule)
+	}
+	if err != nil {
+		fmt.Println("Error creating access rule:", err)
+		return
+	}
+	fmt.Printf("%s %s %s %s\n", rule.ID, rule.Mode, rule.Configuration.Target, rule.Configuration.Value)
+}
+
+func firewallDeleteAccessRule(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	ruleID := c.String("id")
+	value := c.String("value")
+	if ruleID == "" && value == "" {
+		cli.ShowSubcommandHelp(c)
+		fmt.Println(errors.New("id or value not specified"))
+		return
+	}
+	zoneID, orgID, err := accessRuleScope(c)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	del := func(id string) error {
+		switch {
+		case zoneID != "":
+			return api.DeleteZoneAccessRule(zoneID, id)
+		case orgID != "":
+			return api.DeleteOrganizationAccessRule(orgID, id)
+		default:
+			return api.DeleteUserAccessRule(id)
+		}
+	}
+	if ruleID != "" {
+		if err := del(ruleID); err != nil {
+			fmt.Println("Error deleting access rule:", err)
+		}
+		return
+	}
+
+	// Delete every rule matching the value, so that an IP can be unblocked
+	// without looking up the rule first.
+	filter := cloudflare.AccessRule{}
+	filter.Configuration.Value = value
+	var rules []cloudflare.AccessRule
+	switch {
+	case zoneID != "":
+		rules, err = api.ListZoneAccessRules(zoneID, filter)
+	case orgID != "":
+		rules, err = api.ListOrganizationAccessRules(orgID, filter)
+	default:
+		rules, err = api.ListUserAccessRules(filter)
+	}
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if len(rules) == 0 {
+		fmt.Printf("No access rule for %s\n", value)
+		return
+	}
+	for _, r := range rules {
+		// Zone listings include rules inherited from the user or
+		// organization, which can't be deleted through the zone.
+		if zoneID != "" && r.Scope != nil && r.Scope.Type != "zone" {
+			fmt.Printf("Skipping %s: it belongs to the %s\n", r.ID, r.Scope.Type)
+			continue
+		}
+		if err := del(r.ID); err != nil {
+			fmt.Printf("Error deleting access rule %s: %s\n", r.ID, err)
+			continue
+		}
+		fmt.Printf("Deleted %s %s %s\n", r.ID, r.Mode, r.Configuration.Value)
+	}
+}
+
+func firewallValidateExpr(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	expressions := []string(c.Args())
+	if file := c.String("file"); file != "" {
+		var rules []cloudflare.FirewallRule
+		if err := readJSONOrYAMLFile(file, &rules); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		for _, r := range rules {
+			if r.Filter.Expression != "" {
+				expressions = append(expressions, r.Filter.Expression)
+			}
+		}
+	}
+	if len(expressions) == 0 {
+		cli.ShowSubcommandHelp(c)
+		return
+	}
+
+	// Exit with a non-zero status if any expression is invalid, so that
+	// this can be used to check rules in CI.
+	var invalid int
+	for _, expr := range expressions {
+		if err := api.ValidateFilterExpression(expr); err != nil {
+			fmt.Printf("invalid: %s\n  %s\n", expr, err)
+			invalid++
+			continue
+		}
+		fmt.Printf("valid:   %s\n", expr)
+	}
+	if invalid > 0 {
+		os.Exit(1)
+	}
+}
+
+// parseSince parses a time given either as a duration before now, such as
+// 1h or 30m, or as an RFC 3339 timestamp.
+func parseSince(s string) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither a duration such as 1h nor an RFC 3339 time", s)
+	}
+	return t, nil
+}
+
+func firewallEvents(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone"); err != nil {
+		return
+	}
+
+	opts := cloudflare.FirewallEventsOptions{
+		Action: c.String("action"),
+		RuleID: c.String("rule-id"),
+		IP:     c.String("ip"),
+	}
+	var err error
+	if since := c.String("since"); since != "" {
+		if opts.Since, err = parseSince(since); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	if until := c.String("until"); until != "" {
+		if opts.Until, err = parseSince(until); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	zoneID, err := api.ZoneIDByName(c.String("zone"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// Print events as they arrive rather than building a table, so that
+	// long time ranges start printing straight away.
+	it := api.FirewallEventIterator(zoneID, opts)
+	defer it.Close()
+	limit := c.Int("limit")
+	for n := 0; (limit <= 0 || n < limit) && it.Next(); n++ {
+		e := it.Event()
+		fmt.Printf("%s %-12s %-14s %-15s %-2s %s %s%s %s\n",
+			e.OccurredAt.Local().Format("2006-01-02 15:04:05"), e.Action, e.Source, e.IP, e.Country,
+			e.Method, e.Host, e.URI, e.RayID)
+	}
+	if err := it.Err(); err != nil {
+		fmt.Println("Error fetching firewall events:", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/cloudflare/cloudflare-go"
 	"github.com/codegangsta/cli"
@@ -191,6 +192,104 @@ func main() {
 						},
 					},
 				},
+				{
+					Name:   "verify",
+					Action: zoneVerify,
+					Usage:  "Compare the DNS records for a zone with the answers from a resolver",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "zone",
+							Usage: "zone name",
+						},
+						cli.StringFlag{
+							Name:  "resolver",
+							Usage: "address of the resolver or nameserver to query (host[:port])",
+						},
+						cli.BoolFlag{
+							Name:  "all",
+							Usage: "also list records that match",
+						},
+					},
+				},
+				{
+					Name:   "analytics",
+					Action: zoneAnalytics,
+					Usage:  "Show a dashboard of the analytics for a zone",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "zone",
+							Usage: "zone name",
+						},
+						cli.StringFlag{
+							Name:  "since",
+							Value: "24h",
+							Usage: "start of the period, as a duration before now or an RFC 3339 time",
+						},
+						cli.StringFlag{
+							Name:  "until",
+							Usage: "end of the period, as a duration before now or an RFC 3339 time (default now)",
+						},
+						cli.IntFlag{
+							Name:  "top",
+							Value: 5,
+							Usage: "number of countries and status codes to show",
+						},
+						cli.BoolFlag{
+							Name:  "watch",
+							Usage: "refresh the dashboard until interrupted",
+						},
+						cli.DurationFlag{
+							Name:  "interval",
+							Value: time.Minute,
+							Usage: "time between refreshes with --watch (at least 10s)",
+						},
+					},
+				},
+				{
+					Name:      "purge",
+					Action:    zonePurge,
+					Usage:     "Purge cached files, tags, hosts or prefixes",
+					ArgsUsage: "< urls.txt",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "zone",
+							Usage: "zone name",
+						},
+						cli.StringFlag{
+							Name:  "file",
+							Usage: "file of URLs to purge, one per line (default standard input, unless --tag, --host, --prefix or --everything is given)",
+						},
+						cli.StringSliceFlag{
+							Name:  "header",
+							Usage: "request header of the cache key of the URLs, as \"Name: value\" (may be repeated)",
+						},
+						cli.StringSliceFlag{
+							Name:  "tag",
+							Usage: "cache tag to purge (may be repeated)",
+						},
+						cli.StringSliceFlag{
+							Name:  "host",
+							Usage: "host to purge (may be repeated)",
+						},
+						cli.StringSliceFlag{
+							Name:  "prefix",
+							Usage: "URL prefix to purge, such as example.com/images (may be repeated)",
+						},
+						cli.BoolFlag{
+							Name:  "everything",
+							Usage: "purge everything cached for the zone",
+						},
+						cli.IntFlag{
+							Name:  "concurrency",
+							Value: 4,
+							Usage: "number of purge requests in flight at once",
+						},
+						cli.DurationFlag{
+							Name:  "interval",
+							Usage: "minimum time between purge requests",
+						},
+					},
+				},
 				{
 					Name:    "railgun",
 					Aliases: []string{"r"},
@@ -356,6 +455,30 @@ func main() {
 						},
 					},
 				},
+				{
+					Name:   "axfr-import",
+					Action: dnsAXFRImport,
+					Usage:  "Import DNS records from a nameserver using a zone transfer (AXFR)",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "zone",
+							Usage: "zone name",
+						},
+						cli.StringFlag{
+							Name:  "nameserver",
+							Usage: "address of the nameserver to transfer from (host[:port])",
+						},
+						cli.BoolFlag{
+							Name:  "dry-run",
+							Usage: "only list the records that would be created",
+						},
+						cli.IntFlag{
+							Name:  "concurrency",
+							Usage: "number of records to create at once",
+							Value: 4,
+						},
+					},
+				},
 			},
 		},
 
@@ -376,6 +499,593 @@ func main() {
 						},
 					},
 				},
+				{
+					Name:      "test",
+					Aliases:   []string{"t"},
+					Action:    pageRulesTest,
+					Usage:     "Show which Page Rule applies to a URL",
+					ArgsUsage: "<url>",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "zone",
+							Usage: "zone name",
+						},
+					},
+				},
+				{
+					Name:    "create",
+					Aliases: []string{"c"},
+					Action:  pageRulesCreate,
+					Usage:   "Create a Page Rule",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "zone",
+							Usage: "zone name",
+						},
+						cli.StringFlag{
+							Name:  "target",
+							Usage: "URL pattern to match, e.g. *example.com/images/*",
+						},
+						cli.StringSliceFlag{
+							Name:  "action",
+							Usage: "action as id=value, e.g. cache_level=aggressive (may be repeated)",
+						},
+						cli.IntFlag{
+							Name:  "priority",
+							Usage: "priority (higher takes precedence)",
+						},
+						cli.StringFlag{
+							Name:  "status",
+							Usage: "active or paused",
+						},
+						cli.StringFlag{
+							Name:  "file",
+							Usage: "read the Page Rule from a JSON or YAML file",
+						},
+					},
+				},
+				{
+					Name:    "update",
+					Aliases: []string{"u"},
+					Action:  pageRulesUpdate,
+					Usage:   "Update a Page Rule",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "zone",
+							Usage: "zone name",
+						},
+						cli.StringFlag{
+							Name:  "id",
+							Usage: "Page Rule id",
+						},
+						cli.StringFlag{
+							Name:  "target",
+							Usage: "URL pattern to match, e.g. *example.com/images/*",
+						},
+						cli.StringSliceFlag{
+							Name:  "action",
+							Usage: "action as id=value, e.g. cache_level=aggressive (may be repeated)",
+						},
+						cli.IntFlag{
+							Name:  "priority",
+							Usage: "priority (higher takes precedence)",
+						},
+						cli.StringFlag{
+							Name:  "status",
+							Usage: "active or paused",
+						},
+						cli.StringFlag{
+							Name:  "file",
+							Usage: "read the Page Rule from a JSON or YAML file",
+						},
+					},
+				},
+				{
+					Name:    "delete",
+					Aliases: []string{"d"},
+					Action:  pageRulesDelete,
+					Usage:   "Delete a Page Rule",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "zone",
+							Usage: "zone name",
+						},
+						cli.StringFlag{
+							Name:  "id",
+							Usage: "Page Rule id",
+						},
+					},
+				},
+				{
+					Name:      "reorder",
+					Aliases:   []string{"r"},
+					Action:    pageRulesReorder,
+					Usage:     "Change the order of Page Rules, most important first",
+					ArgsUsage: "<id>...",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "zone",
+							Usage: "zone name",
+						},
+					},
+				},
+				{
+					Name:   "export",
+					Action: pageRulesExport,
+					Usage:  "Export the Page Rules of a zone, without IDs, for version control",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "zone",
+							Usage: "zone name",
+						},
+						cli.StringFlag{
+							Name:  "format",
+							Usage: "output format: yaml or json",
+							Value: "yaml",
+						},
+						cli.StringFlag{
+							Name:  "output",
+							Usage: "file to write to instead of standard output",
+						},
+					},
+				},
+				{
+					Name:   "import",
+					Action: pageRulesImport,
+					Usage:  "Make the Page Rules of a zone match an exported file",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "zone",
+							Usage: "zone name",
+						},
+						cli.StringFlag{
+							Name:  "file",
+							Usage: "JSON or YAML file written by export",
+						},
+						cli.StringSliceFlag{
+							Name:  "rewrite-host",
+							Usage: "replace a host in URL patterns as from=to, e.g. example.com=staging.example.com (may be repeated)",
+						},
+						cli.BoolFlag{
+							Name:  "dry-run",
+							Usage: "only show the changes that would be made",
+						},
+					},
+				},
+				{
+					Name:   "pause",
+					Action: pageRulesPause,
+					Usage:  "Pause a Page Rule",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "zone",
+							Usage: "zone name",
+						},
+						cli.StringFlag{
+							Name:  "id",
+							Usage: "Page Rule id",
+						},
+					},
+				},
+				{
+					Name:   "resume",
+					Action: pageRulesResume,
+					Usage:  "Resume a paused Page Rule",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "zone",
+							Usage: "zone name",
+						},
+						cli.StringFlag{
+							Name:  "id",
+							Usage: "Page Rule id",
+						},
+					},
+				},
+			},
+		},
+
+		{
+			Name:  "firewall",
+			Usage: "Firewall rules and events",
+			Subcommands: []cli.Command{
+				{
+					Name:    "list",
+					Aliases: []string{"l"},
+					Action:  firewallAccessRules,
+					Usage:   "List firewall access rules",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "zone",
+							Usage: "zone name (default: the user's rules)",
+						},
+						cli.StringFlag{
+							Name:  "org-id",
+							Usage: "organization ID (default: the user's rules)",
+						},
+						cli.StringFlag{
+							Name:  "mode",
+							Usage: "only list rules with this mode: block, challenge, whitelist or js_challenge",
+						},
+						cli.StringFlag{
+							Name:  "value",
+							Usage: "only list rules matching this IP address, IP range, ASN or country",
+						},
+					},
+				},
+				{
+					Name:      "block",
+					Action:    firewallBlock,
+					Usage:     "Block an IP address, IP range, ASN or country",
+					ArgsUsage: "<ip|ip range|asn|country>",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "zone",
+							Usage: "zone name (default: the user's rules)",
+						},
+						cli.StringFlag{
+							Name:  "org-id",
+							Usage: "organization ID (default: the user's rules)",
+						},
+						cli.StringFlag{
+							Name:  "value",
+							Usage: "IP address, IP range, ASN (AS13335) or country code",
+						},
+						cli.StringFlag{
+							Name:  "notes",
+							Usage: "notes, such as an incident reference",
+						},
+					},
+				},
+				{
+					Name:      "challenge",
+					Action:    firewallChallenge,
+					Usage:     "Challenge an IP address, IP range, ASN or country",
+					ArgsUsage: "<ip|ip range|asn|country>",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "zone",
+							Usage: "zone name (default: the user's rules)",
+						},
+						cli.StringFlag{
+							Name:  "org-id",
+							Usage: "organization ID (default: the user's rules)",
+						},
+						cli.StringFlag{
+							Name:  "value",
+							Usage: "IP address, IP range, ASN (AS13335) or country code",
+						},
+						cli.StringFlag{
+							Name:  "notes",
+							Usage: "notes, such as an incident reference",
+						},
+					},
+				},
+				{
+					Name:      "allow",
+					Action:    firewallAllow,
+					Usage:     "Whitelist an IP address, IP range, ASN or country",
+					ArgsUsage: "<ip|ip range|asn|country>",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "zone",
+							Usage: "zone name (default: the user's rules)",
+						},
+						cli.StringFlag{
+							Name:  "org-id",
+							Usage: "organization ID (default: the user's rules)",
+						},
+						cli.StringFlag{
+							Name:  "value",
+							Usage: "IP address, IP range, ASN (AS13335) or country code",
+						},
+						cli.StringFlag{
+							Name:  "notes",
+							Usage: "notes, such as an incident reference",
+						},
+					},
+				},
+				{
+					Name:    "delete",
+					Aliases: []string{"d"},
+					Action:  firewallDeleteAccessRule,
+					Usage:   "Delete a firewall access rule by ID, or all rules for a value",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "zone",
+							Usage: "zone name (default: the user's rules)",
+						},
+						cli.StringFlag{
+							Name:  "org-id",
+							Usage: "organization ID (default: the user's rules)",
+						},
+						cli.StringFlag{
+							Name:  "id",
+							Usage: "access rule ID",
+						},
+						cli.StringFlag{
+							Name:  "value",
+							Usage: "IP address, IP range, ASN or country code",
+						},
+					},
+				},
+				{
+					Name:   "events",
+					Action: firewallEvents,
+					Usage:  "Show requests acted on by the firewall, most recent first",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "zone",
+							Usage: "zone name",
+						},
+						cli.StringFlag{
+							Name:  "since",
+							Usage: "only show events after this time, as a duration before now (1h) or an RFC 3339 time",
+							Value: "1h",
+						},
+						cli.StringFlag{
+							Name:  "until",
+							Usage: "only show events before this time, as a duration before now or an RFC 3339 time",
+						},
+						cli.StringFlag{
+							Name:  "action",
+							Usage: "only show events with this action, e.g. block or challenge",
+						},
+						cli.StringFlag{
+							Name:  "rule-id",
+							Usage: "only show events for this rule",
+						},
+						cli.StringFlag{
+							Name:  "ip",
+							Usage: "only show events for this client IP address",
+						},
+						cli.IntFlag{
+							Name:  "limit",
+							Usage: "maximum number of events to show (0 = no limit)",
+						},
+					},
+				},
+				{
+					Name:      "validate-expr",
+					Action:    firewallValidateExpr,
+					Usage:     "Check the syntax of firewall rule filter expressions",
+					ArgsUsage: "[expression...]",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "file",
+							Usage: "JSON or YAML file holding a list of firewall rules to check",
+						},
+					},
+				},
+				{
+					Name:  "lockdown",
+					Usage: "Zone Lockdown rules, which restrict URLs to given IP addresses",
+					Subcommands: []cli.Command{
+						{
+							Name:    "list",
+							Aliases: []string{"l"},
+							Action:  zoneLockdownList,
+							Usage:   "List Zone Lockdown rules",
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "zone",
+									Usage: "zone name",
+								},
+							},
+						},
+						{
+							Name:    "create",
+							Aliases: []string{"c"},
+							Action:  zoneLockdownCreate,
+							Usage:   "Create a Zone Lockdown rule",
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "zone",
+									Usage: "zone name",
+								},
+								cli.StringSliceFlag{
+									Name:  "url",
+									Usage: "URL pattern to lock down, e.g. example.com/admin* (may be repeated)",
+								},
+								cli.StringSliceFlag{
+									Name:  "ip",
+									Usage: "IP address or range allowed to access the URLs (may be repeated)",
+								},
+								cli.StringFlag{
+									Name:  "description",
+									Usage: "description of the rule",
+								},
+								cli.IntFlag{
+									Name:  "priority",
+									Usage: "priority of the rule; lower numbers are evaluated first",
+								},
+								cli.BoolFlag{
+									Name:  "paused",
+									Usage: "create the rule paused",
+								},
+							},
+						},
+						{
+							Name:    "update",
+							Aliases: []string{"u"},
+							Action:  zoneLockdownUpdate,
+							Usage:   "Update a Zone Lockdown rule",
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "zone",
+									Usage: "zone name",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "Zone Lockdown rule ID",
+								},
+								cli.StringSliceFlag{
+									Name:  "url",
+									Usage: "URL pattern to lock down, e.g. example.com/admin* (may be repeated)",
+								},
+								cli.StringSliceFlag{
+									Name:  "ip",
+									Usage: "IP address or range allowed to access the URLs (may be repeated)",
+								},
+								cli.StringFlag{
+									Name:  "description",
+									Usage: "description of the rule",
+								},
+								cli.IntFlag{
+									Name:  "priority",
+									Usage: "priority of the rule; lower numbers are evaluated first",
+								},
+								cli.BoolFlag{
+									Name:  "paused",
+									Usage: "pause the rule",
+								},
+							},
+						},
+						{
+							Name:    "delete",
+							Aliases: []string{"d"},
+							Action:  zoneLockdownDelete,
+							Usage:   "Delete a Zone Lockdown rule",
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "zone",
+									Usage: "zone name",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "Zone Lockdown rule ID",
+								},
+							},
+						},
+						{
+							Name:   "pause",
+							Action: zoneLockdownPause,
+							Usage:  "Pause a Zone Lockdown rule",
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "zone",
+									Usage: "zone name",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "Zone Lockdown rule ID",
+								},
+							},
+						},
+						{
+							Name:   "resume",
+							Action: zoneLockdownResume,
+							Usage:  "Resume a paused Zone Lockdown rule",
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "zone",
+									Usage: "zone name",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "Zone Lockdown rule ID",
+								},
+							},
+						},
+					},
+				},
+				{
+					Name:  "ua",
+					Usage: "User-Agent blocking rules",
+					Subcommands: []cli.Command{
+						{
+							Name:    "list",
+							Aliases: []string{"l"},
+							Action:  userAgentRuleList,
+							Usage:   "List User-Agent rules",
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "zone",
+									Usage: "zone name",
+								},
+							},
+						},
+						{
+							Name:      "block",
+							Action:    userAgentRuleBlock,
+							Usage:     "Block or challenge requests with a User-Agent",
+							ArgsUsage: "<user agent>",
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "zone",
+									Usage: "zone name",
+								},
+								cli.StringFlag{
+									Name:  "ua",
+									Usage: "exact User-Agent string",
+								},
+								cli.StringFlag{
+									Name:  "mode",
+									Usage: "block, challenge or js_challenge",
+									Value: "block",
+								},
+								cli.StringFlag{
+									Name:  "description",
+									Usage: "description of the rule",
+								},
+							},
+						},
+						{
+							Name:      "remove",
+							Action:    userAgentRuleRemove,
+							Usage:     "Remove a User-Agent rule by ID, or all rules for a User-Agent",
+							ArgsUsage: "<user agent>",
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "zone",
+									Usage: "zone name",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "User-Agent rule ID",
+								},
+								cli.StringFlag{
+									Name:  "ua",
+									Usage: "exact User-Agent string",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+
+		{
+			Name:  "waf",
+			Usage: "Web Application Firewall",
+			Subcommands: []cli.Command{
+				{
+					Name:   "export",
+					Action: wafExport,
+					Usage:  "Export the WAF configuration of every zone as a baseline",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "format",
+							Usage: "output format: yaml or json",
+							Value: "yaml",
+						},
+						cli.StringFlag{
+							Name:  "output",
+							Usage: "file to write to instead of standard output",
+						},
+					},
+				},
+				{
+					Name:   "drift",
+					Action: wafDrift,
+					Usage:  "Compare the WAF configuration of every zone with a baseline",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "file",
+							Usage: "JSON or YAML baseline written by export",
+						},
+					},
+				},
 			},
 		},
 
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/cloudflare/cloudflare-go"
+	"github.com/codegangsta/cli"
+)
+
+// printZoneLockdown prints a Zone Lockdown rule on one line.
+func printZoneLockdown(ld cloudflare.ZoneLockdown) {
+	var ips []string
+	for _, c := range ld.Configurations {
+		ips = append(ips, c.Value)
+	}
+	status := "active"
+	if ld.Paused {
+		status = "paused"
+	}
+	fmt.Printf("%s %-6s %s allowed from %s", ld.ID, status, strings.Join(ld.URLs, ","), strings.Join(ips, ","))
+	if ld.Description != "" {
+		fmt.Printf(" (%s)", ld.Description)
+	}
+	fmt.Println()
+}
+
+// zoneLockdownFromFlags applies --url, --ip, --description, --priority and
+// --paused to ld.
+func zoneLockdownFromFlags(c *cli.Context, ld cloudflare.ZoneLockdown) (cloudflare.ZoneLockdown, error) {
+	if urls := c.StringSlice("url"); len(urls) > 0 {
+		ld.URLs = urls
+	}
+	if ips := c.StringSlice("ip"); len(ips) > 0 {
+		ld.Configurations = nil
+		for _, ip := range ips {
+			config := cloudflare.ZoneLockdownConfig{Target: "ip", Value: ip}
+			if _, _, err := net.ParseCIDR(ip); err == nil {
+				config.Target = "ip_range"
+			} else if net.ParseIP(ip) == nil {
+				return ld, fmt.Errorf("%q is not an IP address or range", ip)
+			}
+			ld.Configurations = append(ld.Configurations, config)
+		}
+	}
+	if c.IsSet("description") {
+		ld.Description = c.String("description")
+	}
+	if c.IsSet("priority") {
+		ld.Priority = c.Int("priority")
+	}
+	if c.IsSet("paused") {
+		ld.Paused = c.Bool("paused")
+	}
+	return ld, nil
+}
+
+func zoneLockdownList(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone"); err != nil {
+		return
+	}
+	zoneID, err := api.ZoneIDByName(c.String("zone"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	lockdowns, err := api.ListZoneLockdowns(zoneID)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	var output []table
+	for _, ld := range lockdowns {
+		var ips []string
+		for _, c := range ld.Configurations {
+			ips = append(ips, c.Value)
+		}
+		output = append(output, table{
+			"ID":          ld.ID,
+			"URLs":        strings.Join(ld.URLs, ", "),
+			"IPs":         strings.Join(ips, ", "),
+			"Paused":      fmt.Sprintf("%t", ld.Paused),
+			"Description": ld.Description,
+		})
+	}
+	makeTable(output, "ID", "URLs", "IPs", "Paused", "Description")
+}
+
+func zoneLockdownCreate(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone"); err != nil {
+		return
+	}
+	ld, err := zoneLockdownFromFlags(c, cloudflare.ZoneLockdown{})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	zoneID, err := api.ZoneIDByName(c.String("zone"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	ld, err = api.CreateZoneLockdown(zoneID, ld)
+	if err != nil {
+		fmt.Println("Error creating Zone Lockdown rule:", err)
+		return
+	}
+	printZoneLockdown(ld)
+}
+
+func zoneLockdownUpdate(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone", "id"); err != nil {
+		return
+	}
+	zoneID, err := api.ZoneIDByName(c.String("zone"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// Start from the existing rule so that only the given settings change.
+	ld, err := api.ZoneLockdown(zoneID, c.String("id"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	ld, err = zoneLockdownFromFlags(c, ld)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	ld, err = api.UpdateZoneLockdown(zoneID, c.String("id"), ld)
+	if err != nil {
+		fmt.Println("Error updating Zone Lockdown rule:", err)
+		return
+	}
+	printZoneLockdown(ld)
+}
+
+func zoneLockdownPause(c *cli.Context) {
+	zoneLockdownSetPaused(c, true)
+}
+
+func zoneLockdownResume(c *cli.Context) {
+	zoneLockdownSetPaused(c, false)
+}
+
+func zoneLockdownSetPaused(c *cli.Context, paused bool) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone", "id"); err != nil {
+		return
+	}
+	zoneID, err := api.ZoneIDByName(c.String("zone"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	ld, err := api.ZoneLockdown(zoneID, c.String("id"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	ld.Paused = paused
+	ld, err = api.UpdateZoneLockdown(zoneID, c.String("id"), ld)
+	if err != nil {
+		fmt.Println("Error updating Zone Lockdown rule:", err)
+		return
+	}
+	printZoneLockdown(ld)
+}
+
+func zoneLockdownDelete(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone", "id"); err != nil {
+		return
+	}
+	zoneID, err := api.ZoneIDByName(c.String("zone"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	err = api.DeleteZoneLockdown(zoneID, c.String("id"))
+	if err != nil {
+		fmt.Println("Error deleting Zone Lockdown rule:", err)
+	}
+}
@@ -69,26 +69,82 @@ func pageRules(c *cli.Context) {
 
 	fmt.Printf("%3s %-32s %-8s %s\n", "Pri", "ID", "Status", "URL")
 	for _, r := range rules {
+		printPageRule(r)
+	}
+}
+
+// pageRuleActionString formats a Page Rule action for display.
+func pageRuleActionString(a cloudflare.PageRuleAction) string {
+	switch v := a.Value.(type) {
+	case int:
+		return fmt.Sprintf("%s: %d", cloudflare.PageRuleActions[a.ID], v)
+	case cloudflare.ForwardingURL:
+		return fmt.Sprintf("%s: %d - %s", cloudflare.PageRuleActions[a.ID], v.StatusCode, v.URL)
+	case nil:
+		return fmt.Sprintf("%s", cloudflare.PageRuleActions[a.ID])
+	default:
+		vs := fmt.Sprintf("%s", v)
+		return fmt.Sprintf("%s: %s", cloudflare.PageRuleActions[a.ID], strings.Title(strings.Replace(vs, "_", " ", -1)))
+	}
+}
+
+func pageRulesTest(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone"); err != nil {
+		return
+	}
+	if !c.Args().Present() {
+		cli.ShowSubcommandHelp(c)
+		return
+	}
+	zone := c.String("zone")
+	u := c.Args().First()
+
+	zoneID, err := api.ZoneIDByName(zone)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	rules, err := api.ListPageRules(zoneID)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	m, err := cloudflare.NewPageRuleMatcher(rules)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	matches, err := m.MatchAll(u)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if len(matches) == 0 {
+		fmt.Println("No Page Rule matches", u)
+		return
+	}
+
+	for i, match := range matches {
+		r := match.Rule
+		if i == 0 {
+			fmt.Println("Applies:")
+		} else if i == 1 {
+			fmt.Println("Also matches, but shadowed by the rule above:")
+		}
+		fmt.Printf("%3d %s %s\n", r.Priority, r.ID, r.Targets[0].Constraint.Value)
 		var settings []string
-		fmt.Printf("%3d %s %-8s %s\n", r.Priority, r.ID, r.Status, r.Targets[0].Constraint.Value)
 		for _, a := range r.Actions {
-			var s string
-			switch v := a.Value.(type) {
-			case int:
-				s = fmt.Sprintf("%s: %d", cloudflare.PageRuleActions[a.ID], v)
-			case float64:
-				s = fmt.Sprintf("%s: %.f", cloudflare.PageRuleActions[a.ID], v)
-			case map[string]interface{}:
-				s = fmt.Sprintf("%s: %.f - %s", cloudflare.PageRuleActions[a.ID], v["status_code"], v["url"])
-			case nil:
-				s = fmt.Sprintf("%s", cloudflare.PageRuleActions[a.ID])
-			default:
-				vs := fmt.Sprintf("%s", v)
-				s = fmt.Sprintf("%s: %s", cloudflare.PageRuleActions[a.ID], strings.Title(strings.Replace(vs, "_", " ", -1)))
-			}
-			settings = append(settings, s)
+			settings = append(settings, pageRuleActionString(a))
 		}
 		fmt.Println("   ", strings.Join(settings, ", "))
+		if match.ForwardingURL != "" {
+			fmt.Println("    Forwards to:", match.ForwardingURL)
+		}
 	}
 }
 
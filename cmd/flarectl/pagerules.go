@@ -0,0 +1,440 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cloudflare/cloudflare-go"
+	"github.com/codegangsta/cli"
+	"gopkg.in/yaml.v2"
+)
+
+// printPageRule prints a Page Rule in the same format as pagerules list.
+func printPageRule(r cloudflare.PageRule) {
+	var url string
+	if len(r.Targets) > 0 {
+		url = r.Targets[0].Constraint.Value
+	}
+	fmt.Printf("%3d %s %-8s %s\n", r.Priority, r.ID, r.Status, url)
+	var settings []string
+	for _, a := range r.Actions {
+		settings = append(settings, pageRuleActionString(a))
+	}
+	fmt.Println("   ", strings.Join(settings, ", "))
+}
+
+// readPageRuleFile reads a Page Rule from a JSON or YAML file. The fields are
+// the same as in the API.
+func readPageRuleFile(file string) (cloudflare.PageRule, error) {
+	var rule cloudflare.PageRule
+	err := readJSONOrYAMLFile(file, &rule)
+	return rule, err
+}
+
+// readJSONOrYAMLFile decodes a JSON or, if the file name ends in .yaml or
+// .yml, a YAML file into v.
+func readJSONOrYAMLFile(file string, v interface{}) error {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		b, err = yamlToJSON(b)
+		if err != nil {
+			return fmt.Errorf("%s: %s", file, err)
+		}
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%s: %s", file, err)
+	}
+	return nil
+}
+
+// yamlToJSON converts a YAML document to JSON, so that it is decoded with the
+// same rules (and custom unmarshalers) as the API's responses.
+func yamlToJSON(b []byte) ([]byte, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	v, err := jsonValue(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// jsonToYAML encodes v as JSON, honouring its JSON field names and
+// marshalers, and converts the result to YAML.
+func jsonToYAML(v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var y interface{}
+	if err := yaml.Unmarshal(b, &y); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(y)
+}
+
+// jsonValue replaces the map[interface{}]interface{} values produced by the
+// YAML decoder with maps that can be encoded as JSON.
+func jsonValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			ks, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("unsupported key %v", k)
+			}
+			jv, err := jsonValue(val)
+			if err != nil {
+				return nil, err
+			}
+			m[ks] = jv
+		}
+		return m, nil
+	case []interface{}:
+		for i, val := range v {
+			jv, err := jsonValue(val)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = jv
+		}
+	}
+	return v, nil
+}
+
+// pageRuleFromFlags builds a Page Rule from the file given with --file, if
+// any, overridden by --target, --action, --priority and --status.
+func pageRuleFromFlags(c *cli.Context) (cloudflare.PageRule, error) {
+	var rule cloudflare.PageRule
+	if file := c.String("file"); file != "" {
+		var err error
+		rule, err = readPageRuleFile(file)
+		if err != nil {
+			return rule, err
+		}
+	}
+	if c.String("target") != "" {
+		target := cloudflare.PageRuleTarget{Target: "url"}
+		target.Constraint.Operator = "matches"
+		target.Constraint.Value = c.String("target")
+		rule.Targets = []cloudflare.PageRuleTarget{target}
+	}
+	if actions := c.StringSlice("action"); len(actions) > 0 {
+		rule.Actions = nil
+		for _, s := range actions {
+			var id, value string
+			if i := strings.Index(s, "="); i >= 0 {
+				id, value = s[:i], s[i+1:]
+			} else {
+				id = s
+			}
+			a, err := cloudflare.ParsePageRuleAction(id, value)
+			if err != nil {
+				return rule, err
+			}
+			rule.Actions = append(rule.Actions, a)
+		}
+	}
+	if c.IsSet("priority") {
+		rule.Priority = c.Int("priority")
+	}
+	if c.String("status") != "" {
+		rule.Status = c.String("status")
+	}
+	return rule, nil
+}
+
+func pageRulesCreate(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone"); err != nil {
+		return
+	}
+	zone := c.String("zone")
+
+	rule, err := pageRuleFromFlags(c)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if rule.Status == "" {
+		rule.Status = "active"
+	}
+
+	zoneID, err := api.ZoneIDByName(zone)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	rule, err = api.CreatePageRule(zoneID, rule)
+	if err != nil {
+		fmt.Println("Error creating Page Rule:", err)
+		return
+	}
+	printPageRule(rule)
+}
+
+func pageRulesUpdate(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone", "id"); err != nil {
+		return
+	}
+	zone := c.String("zone")
+	ruleID := c.String("id")
+
+	zoneID, err := api.ZoneIDByName(zone)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// Start from the existing rule so that only the given settings change.
+	existing, err := api.PageRule(zoneID, ruleID)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	rule, err := pageRuleFromFlags(c)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if len(rule.Targets) == 0 {
+		rule.Targets = existing.Targets
+	}
+	if len(rule.Actions) == 0 {
+		rule.Actions = existing.Actions
+	}
+	if rule.Priority == 0 {
+		rule.Priority = existing.Priority
+	}
+	if rule.Status == "" {
+		rule.Status = existing.Status
+	}
+
+	rule, err = api.UpdatePageRule(zoneID, ruleID, rule)
+	if err != nil {
+		fmt.Println("Error updating Page Rule:", err)
+		return
+	}
+	printPageRule(rule)
+}
+
+func pageRulesDelete(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone", "id"); err != nil {
+		return
+	}
+	zone := c.String("zone")
+	ruleID := c.String("id")
+
+	zoneID, err := api.ZoneIDByName(zone)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	err = api.DeletePageRule(zoneID, ruleID)
+	if err != nil {
+		fmt.Println("Error deleting Page Rule:", err)
+	}
+}
+
+func pageRulesPause(c *cli.Context) {
+	pageRulesSetStatus(c, "paused")
+}
+
+func pageRulesResume(c *cli.Context) {
+	pageRulesSetStatus(c, "active")
+}
+
+func pageRulesSetStatus(c *cli.Context, status string) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone", "id"); err != nil {
+		return
+	}
+	zone := c.String("zone")
+	ruleID := c.String("id")
+
+	zoneID, err := api.ZoneIDByName(zone)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	rule, err := api.ChangePageRule(zoneID, ruleID, cloudflare.PageRule{Status: status})
+	if err != nil {
+		fmt.Println("Error changing Page Rule:", err)
+		return
+	}
+	printPageRule(rule)
+}
+
+func pageRulesReorder(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone"); err != nil {
+		return
+	}
+	if !c.Args().Present() {
+		cli.ShowSubcommandHelp(c)
+		return
+	}
+	zone := c.String("zone")
+
+	zoneID, err := api.ZoneIDByName(zone)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// Rules not named keep their current order, after the named ones.
+	rules, err := api.ListPageRules(zoneID)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Priority > rules[j].Priority
+	})
+	ids := []string(c.Args())
+	named := make(map[string]bool)
+	for _, id := range ids {
+		named[id] = true
+	}
+	for _, r := range rules {
+		if !named[r.ID] {
+			ids = append(ids, r.ID)
+		}
+	}
+
+	rules, err = api.ReorderPageRules(zoneID, ids)
+	if err != nil {
+		fmt.Println("Error reordering Page Rules:", err)
+		return
+	}
+	fmt.Printf("%3s %-32s %-8s %s\n", "Pri", "ID", "Status", "URL")
+	for _, r := range rules {
+		printPageRule(r)
+	}
+}
+
+func pageRulesExport(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone"); err != nil {
+		return
+	}
+	zone := c.String("zone")
+
+	zoneID, err := api.ZoneIDByName(zone)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	doc, err := api.ExportPageRules(zoneID)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	var b []byte
+	switch c.String("format") {
+	case "json":
+		b, err = json.MarshalIndent(doc, "", "  ")
+		b = append(b, '\n')
+	case "yaml", "":
+		b, err = jsonToYAML(doc)
+	default:
+		err = fmt.Errorf("unknown format %q", c.String("format"))
+	}
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if output := c.String("output"); output != "" {
+		if err := ioutil.WriteFile(output, b, 0644); err != nil {
+			fmt.Println(err)
+		}
+		return
+	}
+	os.Stdout.Write(b)
+}
+
+func pageRulesImport(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone", "file"); err != nil {
+		return
+	}
+	zone := c.String("zone")
+
+	var doc cloudflare.PageRuleDocument
+	if err := readJSONOrYAMLFile(c.String("file"), &doc); err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, rw := range c.StringSlice("rewrite-host") {
+		i := strings.Index(rw, "=")
+		if i < 0 {
+			fmt.Printf("invalid --rewrite-host %q: must be from=to\n", rw)
+			return
+		}
+		doc = doc.RewriteHost(rw[:i], rw[i+1:])
+	}
+
+	zoneID, err := api.ZoneIDByName(zone)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	dryRun := c.Bool("dry-run")
+	result, err := api.ImportPageRules(zoneID, doc, dryRun)
+	for _, change := range result.Changes {
+		fmt.Printf("%-9s ", change.Op)
+		printPageRule(change.Rule)
+	}
+	if err != nil {
+		fmt.Println("Error importing Page Rules:", err)
+		return
+	}
+	if result.Reordered {
+		if dryRun {
+			fmt.Println("Page Rules would be reordered")
+		} else {
+			fmt.Println("Page Rules reordered")
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/cloudflare/cloudflare-go"
+	"github.com/codegangsta/cli"
+)
+
+func userAgentRuleList(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone"); err != nil {
+		return
+	}
+	zoneID, err := api.ZoneIDByName(c.String("zone"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	rules, err := api.ListUserAgentRules(zoneID)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	var output []table
+	for _, r := range rules {
+		output = append(output, table{
+			"ID":          r.ID,
+			"Mode":        r.Mode,
+			"User-Agent":  r.Configuration.Value,
+			"Paused":      fmt.Sprintf("%t", r.Paused),
+			"Description": r.Description,
+		})
+	}
+	makeTable(output, "ID", "Mode", "User-Agent", "Paused", "Description")
+}
+
+func userAgentRuleBlock(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone"); err != nil {
+		return
+	}
+	ua := c.String("ua")
+	if ua == "" {
+		ua = c.Args().First()
+	}
+	if ua == "" {
+		cli.ShowSubcommandHelp(c)
+		return
+	}
+	zoneID, err := api.ZoneIDByName(c.String("zone"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	rule := cloudflare.NewUserAgentRule(c.String("mode"), ua, c.String("description"))
+	rule, err = api.CreateUserAgentRule(zoneID, rule)
+	if err != nil {
+		fmt.Println("Error creating User-Agent rule:", err)
+		return
+	}
+	fmt.Printf("%s %s %q\n", rule.ID, rule.Mode, rule.Configuration.Value)
+}
+
+func userAgentRuleRemove(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone"); err != nil {
+		return
+	}
+	ruleID := c.String("id")
+	ua := c.String("ua")
+	if ua == "" {
+		ua = c.Args().First()
+	}
+	if ruleID == "" && ua == "" {
+		cli.ShowSubcommandHelp(c)
+		return
+	}
+	zoneID, err := api.ZoneIDByName(c.String("zone"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if ruleID != "" {
+		if err := api.DeleteUserAgentRule(zoneID, ruleID); err != nil {
+			fmt.Println("Error deleting User-Agent rule:", err)
+		}
+		return
+	}
+
+	// The API can't filter by User-Agent, so find the rules to remove here.
+	rules, err := api.ListUserAgentRules(zoneID)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	var removed int
+	for _, r := range rules {
+		if r.Configuration.Value != ua {
+			continue
+		}
+		if err := api.DeleteUserAgentRule(zoneID, r.ID); err != nil {
+			fmt.Printf("Error deleting User-Agent rule %s: %s\n", r.ID, err)
+			continue
+		}
+		fmt.Printf("Deleted %s %s %q\n", r.ID, r.Mode, r.Configuration.Value)
+		removed++
+	}
+	if removed == 0 {
+		fmt.Printf("No User-Agent rule for %q\n", ua)
+	}
+}
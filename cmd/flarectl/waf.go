@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/cloudflare/cloudflare-go"
+	"github.com/codegangsta/cli"
+)
+
+func wafExport(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	baseline, err := api.ExportWAFBaseline()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	var b []byte
+	switch c.String("format") {
+	case "json":
+		b, err = json.MarshalIndent(baseline, "", "  ")
+		b = append(b, '\n')
+	case "yaml", "":
+		b, err = jsonToYAML(baseline)
+	default:
+		err = fmt.Errorf("unknown format %q", c.String("format"))
+	}
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if output := c.String("output"); output != "" {
+		if err := ioutil.WriteFile(output, b, 0644); err != nil {
+			fmt.Println(err)
+		}
+		return
+	}
+	os.Stdout.Write(b)
+}
+
+func wafDrift(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "file"); err != nil {
+		return
+	}
+
+	var baseline cloudflare.WAFBaseline
+	if err := readJSONOrYAMLFile(c.String("file"), &baseline); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	drift, err := api.WAFDriftFromBaseline(baseline)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if len(drift) == 0 {
+		fmt.Println("No drift from baseline")
+		return
+	}
+	var output []table
+	for _, d := range drift {
+		output = append(output, table{
+			"Zone":     d.Zone,
+			"Package":  d.Package,
+			"Kind":     d.Kind,
+			"ID":       d.ID,
+			"Name":     d.Name,
+			"Setting":  d.Setting,
+			"Baseline": d.Baseline,
+			"Current":  d.Current,
+		})
+	}
+	makeTable(output, "Zone", "Package", "Kind", "ID", "Name", "Setting", "Baseline", "Current")
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/cloudflare/cloudflare-go"
@@ -196,3 +199,179 @@ func zoneRecords(c *cli.Context) {
 	}
 	makeTable(output, "ID", "Type", "Name", "Content", "Proxied", "TTL")
 }
+
+func zoneVerify(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone", "resolver"); err != nil {
+		return
+	}
+	zone := c.String("zone")
+
+	zoneID, err := api.ZoneIDByName(zone)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	results, err := api.VerifyZoneDNS(zoneID, c.String("resolver"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	var output []table
+	var mismatches int
+	for _, r := range results {
+		var status string
+		switch {
+		case r.Err != nil:
+			status = r.Err.Error()
+		case r.OK():
+			status = "ok"
+		default:
+			status = "mismatch"
+		}
+		if !r.OK() {
+			mismatches++
+		} else if !c.Bool("all") {
+			continue
+		}
+		output = append(output, table{
+			"Name":    r.Name,
+			"Type":    r.Type,
+			"Status":  status,
+			"Missing": strings.Join(r.Missing, ", "),
+			"Extra":   strings.Join(r.Extra, ", "),
+		})
+	}
+	makeTable(output, "Name", "Type", "Status", "Missing", "Extra")
+	fmt.Printf("%d of %d name/type pairs differ\n", mismatches, len(results))
+}
+
+// readURLs reads one URL per line, skipping blank lines and lines starting
+// with #.
+func readURLs(r io.Reader) ([]string, error) {
+	var urls []string
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	return urls, s.Err()
+}
+
+// parseHeaders parses headers given as "Name: value".
+func parseHeaders(headers []string) (map[string]string, error) {
+	m := make(map[string]string)
+	for _, h := range headers {
+		i := strings.Index(h, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid header %q: must be Name: value", h)
+		}
+		m[strings.TrimSpace(h[:i])] = strings.TrimSpace(h[i+1:])
+	}
+	return m, nil
+}
+
+func zonePurge(c *cli.Context) {
+	if err := checkEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := checkFlags(c, "zone"); err != nil {
+		return
+	}
+	zone := c.String("zone")
+
+	pcr := cloudflare.PurgeCacheRequest{
+		Tags:     c.StringSlice("tag"),
+		Hosts:    c.StringSlice("host"),
+		Prefixes: c.StringSlice("prefix"),
+	}
+	// URLs are read from standard input unless something else to purge is
+	// given.
+	file := c.String("file")
+	if file == "" && len(pcr.Tags)+len(pcr.Hosts)+len(pcr.Prefixes) == 0 && !c.Bool("everything") {
+		file = "-"
+	}
+	if file == "" && len(c.StringSlice("header")) > 0 {
+		fmt.Println("--header applies only to URLs")
+		return
+	}
+	if file != "" {
+		r := os.Stdin
+		if file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			defer f.Close()
+			r = f
+		}
+		urls, err := readURLs(r)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		headers, err := parseHeaders(c.StringSlice("header"))
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if len(headers) == 0 {
+			pcr.Files = urls
+		} else {
+			for _, u := range urls {
+				pcr.FilesWithHeaders = append(pcr.FilesWithHeaders, cloudflare.PurgeFile{URL: u, Headers: headers})
+			}
+		}
+	}
+
+	zoneID, err := api.ZoneIDByName(zone)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if c.Bool("everything") {
+		if _, err := api.PurgeEverything(zoneID); err != nil {
+			fmt.Println("Error purging cache:", err)
+		}
+		return
+	}
+
+	total := len(pcr.Files) + len(pcr.FilesWithHeaders) + len(pcr.Tags) + len(pcr.Hosts) + len(pcr.Prefixes)
+	if total == 0 {
+		fmt.Println("Nothing to purge")
+		return
+	}
+	var done int
+	opts := cloudflare.PurgeBatchOptions{
+		Concurrency: c.Int("concurrency"),
+		Interval:    c.Duration("interval"),
+		Progress: func(res cloudflare.PurgeBatchResult) {
+			var items []string
+			for _, l := range [][]string{res.Request.Files, res.Request.Tags, res.Request.Hosts, res.Request.Prefixes} {
+				items = append(items, l...)
+			}
+			for _, f := range res.Request.FilesWithHeaders {
+				items = append(items, f.URL)
+			}
+			done += len(items)
+			if res.Err != nil {
+				fmt.Fprintf(os.Stderr, "Error purging %s: %s\n", strings.Join(items, ", "), res.Err)
+				return
+			}
+			fmt.Fprintf(os.Stderr, "Purged %d/%d\n", done, total)
+		},
+	}
+	if _, err := api.PurgeCacheBatch(zoneID, pcr, opts); err != nil {
+		fmt.Println("Error purging cache:", err)
+	}
+}
@@ -0,0 +1,143 @@
+package cloudflare
+
+import (
+	"sort"
+	"strings"
+)
+
+// Colo describes a Cloudflare data center, identified by the IATA code of
+// the nearest major airport.
+type Colo struct {
+	Code      string
+	City      string
+	Country   string // ISO 3166-1 alpha-2 code
+	Region    string
+	Latitude  float64
+	Longitude float64
+}
+
+// Colos holds the metadata of Cloudflare data centers, by code. It covers
+// the main data centers only; LookupColo reports the others as unknown.
+var Colos = map[string]Colo{
+	"AKL": {"AKL", "Auckland", "NZ", "Oceania", -37.0082, 174.7850},
+	"AMS": {"AMS", "Amsterdam", "NL", "Europe", 52.3086, 4.7639},
+	"ARN": {"ARN", "Stockholm", "SE", "Europe", 59.6519, 17.9186},
+	"ATL": {"ATL", "Atlanta", "US", "North America", 33.6367, -84.4281},
+	"BOM": {"BOM", "Mumbai", "IN", "Asia", 19.0887, 72.8679},
+	"BOS": {"BOS", "Boston", "US", "North America", 42.3643, -71.0052},
+	"CAI": {"CAI", "Cairo", "EG", "Africa", 30.1219, 31.4056},
+	"CDG": {"CDG", "Paris", "FR", "Europe", 49.0097, 2.5479},
+	"CPH": {"CPH", "Copenhagen", "DK", "Europe", 55.6180, 12.6508},
+	"DEN": {"DEN", "Denver", "US", "North America", 39.8561, -104.6737},
+	"DFW": {"DFW", "Dallas", "US", "North America", 32.8968, -97.0380},
+	"DOH": {"DOH", "Doha", "QA", "Middle East", 25.2731, 51.6081},
+	"DUB": {"DUB", "Dublin", "IE", "Europe", 53.4213, -6.2701},
+	"DXB": {"DXB", "Dubai", "AE", "Middle East", 25.2528, 55.3644},
+	"EWR": {"EWR", "Newark", "US", "North America", 40.6925, -74.1687},
+	"EZE": {"EZE", "Buenos Aires", "AR", "South America", -34.8222, -58.5358},
+	"FRA": {"FRA", "Frankfurt", "DE", "Europe", 50.0333, 8.5706},
+	"GRU": {"GRU", "São Paulo", "BR", "South America", -23.4356, -46.4731},
+	"HKG": {"HKG", "Hong Kong", "HK", "Asia", 22.3080, 113.9185},
+	"IAD": {"IAD", "Ashburn", "US", "North America", 38.9445, -77.4558},
+	"ICN": {"ICN", "Seoul", "KR", "Asia", 37.4691, 126.4510},
+	"JNB": {"JNB", "Johannesburg", "ZA", "Africa", -26.1392, 28.2460},
+	"KIX": {"KIX", "Osaka", "JP", "Asia", 34.4347, 135.2440},
+	"LAX": {"LAX", "Los Angeles", "US", "North America", 33.9425, -118.4081},
+	"LHR": {"LHR", "London", "GB", "Europe", 51.4700, -0.4543},
+	"MAD": {"MAD", "Madrid", "ES", "Europe", 40.4719, -3.5626},
+	"MAN": {"MAN", "Manchester", "GB", "Europe", 53.3537, -2.2750},
+	"MEL": {"MEL", "Melbourne", "AU", "Oceania", -37.6690, 144.8410},
+	"MIA": {"MIA", "Miami", "US", "North America", 25.7959, -80.2870},
+	"MRS": {"MRS", "Marseille", "FR", "Europe", 43.4393, 5.2214},
+	"MXP": {"MXP", "Milan", "IT", "Europe", 45.6306, 8.7281},
+	"NRT": {"NRT", "Tokyo", "JP", "Asia", 35.7720, 140.3929},
+	"ORD": {"ORD", "Chicago", "US", "North America", 41.9742, -87.9073},
+	"PRG": {"PRG", "Prague", "CZ", "Europe", 50.1008, 14.2600},
+	"SCL": {"SCL", "Santiago", "CL", "South America", -33.3930, -70.7858},
+	"SEA": {"SEA", "Seattle", "US", "North America", 47.4502, -122.3088},
+	"SIN": {"SIN", "Singapore", "SG", "Asia", 1.3644, 103.9915},
+	"SJC": {"SJC", "San Jose", "US", "North America", 37.3639, -121.9289},
+	"SYD": {"SYD", "Sydney", "AU", "Oceania", -33.9399, 151.1753},
+	"TPE": {"TPE", "Taipei", "TW", "Asia", 25.0797, 121.2342},
+	"VIE": {"VIE", "Vienna", "AT", "Europe", 48.1103, 16.5697},
+	"WAW": {"WAW", "Warsaw", "PL", "Europe", 52.1657, 20.9671},
+	"YUL": {"YUL", "Montréal", "CA", "North America", 45.4706, -73.7408},
+	"YYZ": {"YYZ", "Toronto", "CA", "North America", 43.6777, -79.6248},
+	"ZRH": {"ZRH", "Zurich", "CH", "Europe", 47.4582, 8.5555},
+}
+
+// LookupColo returns the metadata of a data center by its code, ignoring
+// case. If the data center is unknown, only the Code of the result is set
+// and ok is false.
+func LookupColo(code string) (colo Colo, ok bool) {
+	code = strings.ToUpper(code)
+	colo, ok = Colos[code]
+	if !ok {
+		colo.Code = code
+	}
+	return colo, ok
+}
+
+// Colo returns the metadata of the data center the analytics are for.
+func (c ZoneAnalyticsColocation) Colo() (Colo, bool) {
+	return LookupColo(c.ColocationID)
+}
+
+// ZoneAnalyticsGroup holds the analytics of a group of data centers.
+type ZoneAnalyticsGroup struct {
+	// Key is the name of the group, such as a region or country.
+	Key    string
+	Totals ZoneAnalytics
+}
+
+// GroupColocationAnalytics sums the timeseries of each data center and adds
+// them up by the key returned for their metadata, such as the country.
+// Data centers for which key returns "", including unknown ones when grouping
+// by country or region, are grouped under "Unknown". The groups are ordered
+// by the number of requests, highest first.
+func GroupColocationAnalytics(colos []ZoneAnalyticsColocation, key func(Colo) string) []ZoneAnalyticsGroup {
+	totals := make(map[string]ZoneAnalytics)
+	for _, c := range colos {
+		colo, _ := c.Colo()
+		k := key(colo)
+		if k == "" {
+			k = "Unknown"
+		}
+		for _, a := range c.Timeseries {
+			totals[k] = totals[k].Add(a)
+		}
+	}
+	groups := make([]ZoneAnalyticsGroup, 0, len(totals))
+	for k, t := range totals {
+		groups = append(groups, ZoneAnalyticsGroup{Key: k, Totals: t})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Totals.Requests.All != groups[j].Totals.Requests.All {
+			return groups[i].Totals.Requests.All > groups[j].Totals.Requests.All
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	return groups
+}
+
+// ColocationAnalyticsByRegion adds up the analytics of data centers by
+// region, busiest first.
+func ColocationAnalyticsByRegion(colos []ZoneAnalyticsColocation) []ZoneAnalyticsGroup {
+	return GroupColocationAnalytics(colos, func(c Colo) string { return c.Region })
+}
+
+// ColocationAnalyticsByCountry adds up the analytics of data centers by
+// country, busiest first.
+func ColocationAnalyticsByCountry(colos []ZoneAnalyticsColocation) []ZoneAnalyticsGroup {
+	return GroupColocationAnalytics(colos, func(c Colo) string { return c.Country })
+}
+
+// TopColocations returns the totals of the n data centers that served the
+// most requests, busiest first, keyed by data center code.
+func TopColocations(colos []ZoneAnalyticsColocation, n int) []ZoneAnalyticsGroup {
+	groups := GroupColocationAnalytics(colos, func(c Colo) string { return c.Code })
+	if n >= 0 && n < len(groups) {
+		groups = groups[:n]
+	}
+	return groups
+}
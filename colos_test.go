@@ -0,0 +1,58 @@
+package cloudflare
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupColo(t *testing.T) {
+	c, ok := LookupColo("sjc")
+	assert.True(t, ok)
+	assert.Equal(t, "San Jose", c.City)
+	assert.Equal(t, "US", c.Country)
+	assert.Equal(t, "North America", c.Region)
+
+	c, ok = LookupColo("xyz")
+	assert.False(t, ok)
+	assert.Equal(t, Colo{Code: "XYZ"}, c)
+
+	for code, c := range Colos {
+		assert.Equal(t, code, c.Code)
+	}
+}
+
+func TestGroupColocationAnalytics(t *testing.T) {
+	colos := []ZoneAnalyticsColocation{
+		{ColocationID: "SJC", Timeseries: []ZoneAnalytics{
+			testZoneAnalytics("2018-08-01T00:00:00Z", 10, 5),
+			testZoneAnalytics("2018-08-01T01:00:00Z", 20, 5),
+		}},
+		{ColocationID: "LAX", Timeseries: []ZoneAnalytics{testZoneAnalytics("2018-08-01T00:00:00Z", 5, 1)}},
+		{ColocationID: "LHR", Timeseries: []ZoneAnalytics{testZoneAnalytics("2018-08-01T00:00:00Z", 50, 1)}},
+		{ColocationID: "XYZ", Timeseries: []ZoneAnalytics{testZoneAnalytics("2018-08-01T00:00:00Z", 1, 1)}},
+	}
+
+	regions := ColocationAnalyticsByRegion(colos)
+	if assert.Len(t, regions, 3) {
+		assert.Equal(t, "Europe", regions[0].Key)
+		assert.Equal(t, 50, regions[0].Totals.Requests.All)
+		assert.Equal(t, "North America", regions[1].Key)
+		assert.Equal(t, 35, regions[1].Totals.Requests.All)
+		assert.Equal(t, "Unknown", regions[2].Key)
+	}
+
+	countries := ColocationAnalyticsByCountry(colos)
+	if assert.Len(t, countries, 3) {
+		assert.Equal(t, "GB", countries[0].Key)
+		assert.Equal(t, "US", countries[1].Key)
+	}
+
+	top := TopColocations(colos, 2)
+	if assert.Len(t, top, 2) {
+		assert.Equal(t, "LHR", top[0].Key)
+		assert.Equal(t, "SJC", top[1].Key)
+		assert.Equal(t, 30, top[1].Totals.Requests.All)
+	}
+	assert.Len(t, TopColocations(colos, 10), 4)
+}
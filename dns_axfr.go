@@ -0,0 +1,152 @@
+package cloudflare
+
+import (
+	"net"
+	"strings"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+)
+
+// minimumRecordTTL is the lowest TTL accepted by the API other than 1, which
+// means "automatic".
+const minimumRecordTTL = 120
+
+// ZoneTransfer performs a zone transfer (AXFR) of zone from the nameserver at
+// addr, which is a host with an optional port (default 53), and converts the
+// result into records suitable for CreateDNSRecord or CreateDNSRecords.
+//
+// The SOA and the NS records at the zone apex are dropped, since Cloudflare
+// provides its own; delegations to subdomains are kept. Those, and any
+// records of a type Cloudflare does not support, are returned in skipped in
+// zone file format.
+func ZoneTransfer(zone, addr string) (records []DNSRecord, skipped []string, err error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "53")
+	}
+	apex := dns.Fqdn(zone)
+
+	m := new(dns.Msg)
+	m.SetAxfr(apex)
+	t := new(dns.Transfer)
+	env, err := t.In(m, addr)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "zone transfer failed")
+	}
+
+	var soa int
+	for e := range env {
+		if e.Error != nil {
+			// Drain the channel so the transfer goroutine can exit.
+			for range env {
+			}
+			return nil, nil, errors.Wrap(e.Error, "zone transfer failed")
+		}
+		for _, rr := range e.RR {
+			switch rr.(type) {
+			case *dns.SOA:
+				// The SOA opens and closes the transfer.
+				soa++
+				if soa == 1 {
+					skipped = append(skipped, rr.String())
+				}
+				continue
+			case *dns.NS:
+				if strings.EqualFold(rr.Header().Name, apex) {
+					skipped = append(skipped, rr.String())
+					continue
+				}
+			}
+			rec, ok := dnsRecordFromRR(rr)
+			if !ok {
+				skipped = append(skipped, rr.String())
+				continue
+			}
+			records = append(records, rec)
+		}
+	}
+	if soa == 0 {
+		return nil, nil, errors.New("zone transfer failed: no SOA record received")
+	}
+	return records, skipped, nil
+}
+
+// dnsRecordFromRR converts a resource record into a DNSRecord. It reports
+// false if the record type cannot be represented.
+func dnsRecordFromRR(rr dns.RR) (DNSRecord, bool) {
+	h := rr.Header()
+	rec := DNSRecord{
+		Type: dns.TypeToString[h.Rrtype],
+		Name: trimDot(h.Name),
+		TTL:  int(h.Ttl),
+	}
+	if rec.TTL < minimumRecordTTL {
+		rec.TTL = minimumRecordTTL
+	}
+
+	switch v := rr.(type) {
+	case *dns.A:
+		rec.Content = v.A.String()
+	case *dns.AAAA:
+		rec.Content = v.AAAA.String()
+	case *dns.CNAME:
+		rec.Content = trimDot(v.Target)
+	case *dns.NS:
+		rec.Content = trimDot(v.Ns)
+	case *dns.PTR:
+		rec.Content = trimDot(v.Ptr)
+	case *dns.MX:
+		rec.Content = trimDot(v.Mx)
+		rec.Priority = int(v.Preference)
+	case *dns.TXT:
+		rec.Content = strings.Join(v.Txt, "")
+	case *dns.SPF:
+		rec.Content = strings.Join(v.Txt, "")
+	case *dns.SRV:
+		// The API takes SRV records as structured data; the owner name is
+		// split into service, protocol and name.
+		labels := dns.SplitDomainName(h.Name)
+		if len(labels) < 3 {
+			return DNSRecord{}, false
+		}
+		rec.Data = map[string]interface{}{
+			"service":  labels[0],
+			"proto":    labels[1],
+			"name":     strings.Join(labels[2:], "."),
+			"priority": v.Priority,
+			"weight":   v.Weight,
+			"port":     v.Port,
+			"target":   trimDot(v.Target),
+		}
+	case *dns.CAA:
+		rec.Data = map[string]interface{}{
+			"flags": v.Flag,
+			"tag":   v.Tag,
+			"value": v.Value,
+		}
+	default:
+		return DNSRecord{}, false
+	}
+	return rec, true
+}
+
+// trimDot removes the trailing dot from a fully-qualified domain name.
+func trimDot(name string) string {
+	if name == "." {
+		return name
+	}
+	return strings.TrimSuffix(name, ".")
+}
+
+// ImportZoneTransfer transfers zone from the nameserver at addr (see
+// ZoneTransfer) and creates the resulting records in the zone identifier.
+// The records that were not imported are returned in skipped, in zone file
+// format.
+func (api *API) ImportZoneTransfer(zoneID, zone, addr string, opts DNSBatchOptions) (results []DNSBatchResult, skipped []string, err error) {
+	records, skipped, err := ZoneTransfer(zone, addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	results, err = api.CreateDNSRecords(zoneID, records, opts)
+	return results, skipped, err
+}
@@ -0,0 +1,122 @@
+package cloudflare
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+)
+
+// startAXFRServer serves a zone transfer of zone from a local TCP listener
+// and returns its address.
+func startAXFRServer(t *testing.T, zone string, rrs []string) (string, func()) {
+	var answer []dns.RR
+	for _, s := range rrs {
+		rr, err := dns.NewRR(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		answer = append(answer, rr)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := dns.NewServeMux()
+	mux.HandleFunc(zone, func(w dns.ResponseWriter, r *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(r)
+		if r.Question[0].Qtype != dns.TypeAXFR {
+			m.Rcode = dns.RcodeRefused
+		} else {
+			m.Answer = answer
+		}
+		w.WriteMsg(m)
+	})
+	server := &dns.Server{Listener: l, Handler: mux}
+	go server.ActivateAndServe()
+	return l.Addr().String(), func() { server.Shutdown() }
+}
+
+func TestZoneTransfer(t *testing.T) {
+	soa := "example.com. 3600 IN SOA ns1.example.com. hostmaster.example.com. 1 7200 3600 1209600 300"
+	addr, stop := startAXFRServer(t, "example.com.", []string{
+		soa,
+		"example.com. 3600 IN NS ns1.example.com.",
+		"example.com. 300 IN A 192.0.2.1",
+		"example.com. 300 IN MX 10 mail.example.com.",
+		"www.example.com. 60 IN CNAME example.com.",
+		"v6.example.com. 300 IN AAAA 2001:db8::1",
+		"example.com. 300 IN TXT \"v=spf1 \" \"-all\"",
+		"_sip._tcp.example.com. 300 IN SRV 10 20 5060 sip.example.com.",
+		"sub.example.com. 3600 IN NS ns.sub.example.com.",
+		"example.com. 300 IN HINFO \"x86\" \"linux\"",
+		soa,
+	})
+	defer stop()
+
+	records, skipped, err := ZoneTransfer("example.com", addr)
+	if !assert.NoError(t, err) {
+		return
+	}
+	want := []DNSRecord{
+		{Type: "A", Name: "example.com", Content: "192.0.2.1", TTL: 300},
+		{Type: "MX", Name: "example.com", Content: "mail.example.com", TTL: 300, Priority: 10},
+		{Type: "CNAME", Name: "www.example.com", Content: "example.com", TTL: 120},
+		{Type: "AAAA", Name: "v6.example.com", Content: "2001:db8::1", TTL: 300},
+		{Type: "TXT", Name: "example.com", Content: "v=spf1 -all", TTL: 300},
+		{Type: "SRV", Name: "_sip._tcp.example.com", TTL: 300, Data: map[string]interface{}{
+			"service":  "_sip",
+			"proto":    "_tcp",
+			"name":     "example.com",
+			"priority": uint16(10),
+			"weight":   uint16(20),
+			"port":     uint16(5060),
+			"target":   "sip.example.com",
+		}},
+		{Type: "NS", Name: "sub.example.com", Content: "ns.sub.example.com", TTL: 3600},
+	}
+	assert.Equal(t, want, records)
+	assert.Len(t, skipped, 3)
+}
+
+func TestZoneTransferRefused(t *testing.T) {
+	addr, stop := startAXFRServer(t, "example.com.", nil)
+	defer stop()
+
+	_, _, err := ZoneTransfer("example.org", addr)
+	assert.Error(t, err)
+}
+
+func TestImportZoneTransfer(t *testing.T) {
+	setup()
+	defer teardown()
+
+	addr, stop := startAXFRServer(t, "example.com.", []string{
+		"example.com. 3600 IN SOA ns1.example.com. hostmaster.example.com. 1 7200 3600 1209600 300",
+		"example.com. 300 IN A 192.0.2.1",
+		"example.com. 300 IN HINFO \"x86\" \"linux\"",
+		"example.com. 3600 IN SOA ns1.example.com. hostmaster.example.com. 1 7200 3600 1209600 300",
+	})
+	defer stop()
+
+	mux.HandleFunc("/zones/abc/dns_records", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"id":"rec","type":"A","name":"example.com"}}`)
+	})
+
+	results, skipped, err := client.ImportZoneTransfer("abc", "example.com", addr, DNSBatchOptions{})
+	if assert.NoError(t, err) {
+		assert.Len(t, results, 1)
+		assert.Equal(t, "rec", results[0].ID)
+		if assert.Len(t, skipped, 2) {
+			assert.Contains(t, skipped[0], "SOA")
+			assert.Contains(t, skipped[1], "HINFO")
+		}
+	}
+}
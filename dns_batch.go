@@ -0,0 +1,216 @@
+package cloudflare
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// defaultBatchConcurrency is the number of requests a batch operation keeps
+// in flight when no concurrency is given.
+const defaultBatchConcurrency = 4
+
+// DNSBatchOptions configures CreateDNSRecords and DeleteDNSRecords.
+type DNSBatchOptions struct {
+	// Concurrency is the maximum number of API requests in flight at once.
+	// Defaults to 4.
+	Concurrency int
+	// Completed contains the results of an earlier, interrupted run (see
+	// ReadDNSBatchResults). Records that succeeded there are not sent again.
+	Completed []DNSBatchResult
+	// Results, if non-nil, receives each result as a line of JSON as soon as
+	// it is known, so that a results file can be used to resume later.
+	Results io.Writer
+}
+
+// DNSBatchResult is the outcome of a single record in a batch operation.
+type DNSBatchResult struct {
+	// Record is the record as it was passed to the batch operation.
+	Record DNSRecord
+	// ID is the identifier of the created (or deleted) record.
+	ID string
+	// Err is nil if the operation succeeded.
+	Err error
+}
+
+// dnsBatchResultJSON is the on-disk form of a DNSBatchResult.
+type dnsBatchResultJSON struct {
+	Record DNSRecord `json:"record"`
+	ID     string    `json:"id,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// MarshalJSON encodes the result, storing the error as its message.
+func (r DNSBatchResult) MarshalJSON() ([]byte, error) {
+	j := dnsBatchResultJSON{Record: r.Record, ID: r.ID}
+	if r.Err != nil {
+		j.Error = r.Err.Error()
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes a result written by MarshalJSON.
+func (r *DNSBatchResult) UnmarshalJSON(data []byte) error {
+	var j dnsBatchResultJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	r.Record = j.Record
+	r.ID = j.ID
+	r.Err = nil
+	if j.Error != "" {
+		r.Err = errors.New(j.Error)
+	}
+	return nil
+}
+
+// ReadDNSBatchResults reads results written to DNSBatchOptions.Results, one
+// JSON object per line. Later lines for the same record take precedence when
+// passed back in as DNSBatchOptions.Completed.
+func ReadDNSBatchResults(r io.Reader) ([]DNSBatchResult, error) {
+	var results []DNSBatchResult
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+	for s.Scan() {
+		if len(s.Bytes()) == 0 {
+			continue
+		}
+		var res DNSBatchResult
+		if err := json.Unmarshal(s.Bytes(), &res); err != nil {
+			return nil, errors.Wrap(err, "could not decode batch result")
+		}
+		results = append(results, res)
+	}
+	if err := s.Err(); err != nil {
+		return nil, errors.Wrap(err, "could not read batch results")
+	}
+	return results, nil
+}
+
+// CreateDNSRecords creates many DNS records for the zone identifier, running
+// up to opts.Concurrency requests at once.
+//
+// A failure does not stop the batch: every record gets a result, returned in
+// the same order as records. The error is non-nil if any record failed.
+func (api *API) CreateDNSRecords(zoneID string, records []DNSRecord, opts DNSBatchOptions) ([]DNSBatchResult, error) {
+	return api.dnsBatch(records, opts, dnsBatchCreateKey, func(rr DNSRecord) (string, error) {
+		res, err := api.CreateDNSRecord(zoneID, rr)
+		if err != nil {
+			return "", err
+		}
+		if !res.Success {
+			return "", errors.New(errRequestNotSuccessful)
+		}
+		return res.Result.ID, nil
+	})
+}
+
+// DeleteDNSRecords deletes many DNS records for the zone identifier, running
+// up to opts.Concurrency requests at once. Each record must have its ID set.
+//
+// A failure does not stop the batch: every record gets a result, returned in
+// the same order as records. The error is non-nil if any record failed.
+func (api *API) DeleteDNSRecords(zoneID string, records []DNSRecord, opts DNSBatchOptions) ([]DNSBatchResult, error) {
+	return api.dnsBatch(records, opts, dnsBatchDeleteKey, func(rr DNSRecord) (string, error) {
+		if rr.ID == "" {
+			return "", errors.New("record ID must not be empty")
+		}
+		if err := api.DeleteDNSRecord(zoneID, rr.ID); err != nil {
+			return "", err
+		}
+		return rr.ID, nil
+	})
+}
+
+// dnsBatchCreateKey identifies a record to be created across runs. Data is
+// included as JSON, which orders map keys and encodes numbers the same way
+// whether they were set by the caller or decoded from a results file.
+func dnsBatchCreateKey(rr DNSRecord) string {
+	var data []byte
+	if rr.Data != nil {
+		data, _ = json.Marshal(rr.Data)
+	}
+	return rr.Type + "\x00" + rr.Name + "\x00" + rr.Content + "\x00" + strconv.Itoa(rr.Priority) + "\x00" + string(data)
+}
+
+// dnsBatchDeleteKey identifies a record to be deleted across runs.
+func dnsBatchDeleteKey(rr DNSRecord) string {
+	return rr.ID
+}
+
+// dnsBatch runs op for every record with bounded concurrency, skipping those
+// that already succeeded in opts.Completed.
+func (api *API) dnsBatch(records []DNSRecord, opts DNSBatchOptions, key func(DNSRecord) string, op func(DNSRecord) (string, error)) ([]DNSBatchResult, error) {
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	done := make(map[string]DNSBatchResult)
+	for _, res := range opts.Completed {
+		k := key(res.Record)
+		if res.Err == nil {
+			done[k] = res
+		} else {
+			delete(done, k)
+		}
+	}
+
+	results := make([]DNSBatchResult, len(records))
+	var mu sync.Mutex
+	var writeErr error
+	record := func(i int, res DNSBatchResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[i] = res
+		if opts.Results == nil || writeErr != nil {
+			return
+		}
+		b, err := json.Marshal(res)
+		if err == nil {
+			_, err = opts.Results.Write(append(b, '\n'))
+		}
+		writeErr = err
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				rr := records[i]
+				id, err := op(rr)
+				record(i, DNSBatchResult{Record: rr, ID: id, Err: err})
+			}
+		}()
+	}
+	for i, rr := range records {
+		if res, ok := done[key(rr)]; ok {
+			results[i] = res
+			continue
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if writeErr != nil {
+		return results, errors.Wrap(writeErr, "could not write batch results")
+	}
+	var failed int
+	for _, res := range results {
+		if res.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, errors.Errorf("%d of %d records failed", failed, len(records))
+	}
+	return results, nil
+}
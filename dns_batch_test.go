@@ -0,0 +1,131 @@
+package cloudflare
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateDNSRecords(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	var created []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method, "Expected method 'POST', got %s", r.Method)
+		var rr DNSRecord
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&rr)) {
+			return
+		}
+		if rr.Name == "bad.example.com" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"success":false,"errors":[{"code":1004,"message":"DNS Validation Error"}]}`)
+			return
+		}
+		mu.Lock()
+		created = append(created, rr.Name)
+		mu.Unlock()
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success":true,"errors":[],"messages":[],"result":{"id":"id-%s","name":%q}}`, rr.Name, rr.Name)
+	}
+	mux.HandleFunc("/zones/abc/dns_records", handler)
+
+	records := []DNSRecord{
+		{Type: "A", Name: "a.example.com", Content: "192.0.2.1"},
+		{Type: "A", Name: "bad.example.com", Content: "192.0.2.2"},
+		{Type: "A", Name: "c.example.com", Content: "192.0.2.3"},
+	}
+	var out bytes.Buffer
+	results, err := client.CreateDNSRecords("abc", records, DNSBatchOptions{Concurrency: 2, Results: &out})
+	assert.EqualError(t, err, "1 of 3 records failed")
+	if assert.Len(t, results, 3) {
+		assert.Equal(t, "id-a.example.com", results[0].ID)
+		assert.Error(t, results[1].Err)
+		assert.Equal(t, records[1], results[1].Record)
+		assert.Equal(t, "id-c.example.com", results[2].ID)
+	}
+	assert.Len(t, created, 2)
+
+	// Resuming from the results file only retries the failed record.
+	completed, err := ReadDNSBatchResults(strings.NewReader(out.String()))
+	assert.NoError(t, err)
+	assert.Len(t, completed, 3)
+	created = nil
+	results, err = client.CreateDNSRecords("abc", records, DNSBatchOptions{Completed: completed})
+	assert.EqualError(t, err, "1 of 3 records failed")
+	assert.Empty(t, created)
+	assert.Equal(t, "id-a.example.com", results[0].ID)
+	assert.Equal(t, "id-c.example.com", results[2].ID)
+}
+
+func TestCreateDNSRecordsResumeKey(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var created []string
+	mux.HandleFunc("/zones/abc/dns_records", func(w http.ResponseWriter, r *http.Request) {
+		var rr DNSRecord
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&rr)) {
+			return
+		}
+		created = append(created, fmt.Sprintf("%s %d", rr.Content, rr.Priority))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success":true,"errors":[],"messages":[],"result":{"id":"id-%d"}}`, rr.Priority)
+	})
+
+	// Records differing only in priority or data are distinct.
+	mx10 := DNSRecord{Type: "MX", Name: "example.com", Content: "mail.example.com", Priority: 10}
+	mx20 := DNSRecord{Type: "MX", Name: "example.com", Content: "mail.example.com", Priority: 20}
+	srv := func(port int) DNSRecord {
+		return DNSRecord{Type: "SRV", Name: "_sip._tcp.example.com", Data: map[string]interface{}{
+			"service": "_sip", "proto": "_tcp", "name": "example.com",
+			"priority": 10, "weight": 20, "port": port, "target": "sip.example.com",
+		}}
+	}
+
+	// Only the first MX and SRV records were created in an earlier run,
+	// whose results were read back from a file.
+	var out bytes.Buffer
+	_, err := client.CreateDNSRecords("abc", []DNSRecord{mx10, srv(5060)}, DNSBatchOptions{Results: &out})
+	assert.NoError(t, err)
+	completed, err := ReadDNSBatchResults(strings.NewReader(out.String()))
+	assert.NoError(t, err)
+
+	created = nil
+	results, err := client.CreateDNSRecords("abc", []DNSRecord{mx10, mx20, srv(5060), srv(5061)},
+		DNSBatchOptions{Concurrency: 1, Completed: completed})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mail.example.com 20", " 0"}, created)
+	assert.Len(t, results, 4)
+}
+
+func TestDeleteDNSRecords(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method, "Expected method 'DELETE', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"id":"372e67954025e0ba6aaa6d586b9e0b59"}}`)
+	}
+	mux.HandleFunc("/zones/abc/dns_records/372e67954025e0ba6aaa6d586b9e0b59", handler)
+
+	records := []DNSRecord{
+		{ID: "372e67954025e0ba6aaa6d586b9e0b59"},
+		{Name: "no-id.example.com"},
+	}
+	results, err := client.DeleteDNSRecords("abc", records, DNSBatchOptions{})
+	assert.EqualError(t, err, "1 of 2 records failed")
+	if assert.Len(t, results, 2) {
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, "372e67954025e0ba6aaa6d586b9e0b59", results[0].ID)
+		assert.EqualError(t, results[1].Err, "record ID must not be empty")
+	}
+}
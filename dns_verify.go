@@ -0,0 +1,201 @@
+package cloudflare
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+)
+
+// DNSVerifyResult compares the records Cloudflare holds for one name and type
+// with the answers given by a resolver.
+type DNSVerifyResult struct {
+	Name string
+	Type string
+	// Expected holds the content of the Cloudflare records, normalised to the
+	// form used for comparison.
+	Expected []string
+	// Missing holds expected values that the resolver did not return.
+	Missing []string
+	// Extra holds values returned by the resolver that Cloudflare does not
+	// have.
+	Extra []string
+	// Err is set if the resolver could not be queried.
+	Err error
+}
+
+// OK reports whether the resolver returned exactly the expected answers.
+func (r DNSVerifyResult) OK() bool {
+	return r.Err == nil && len(r.Missing) == 0 && len(r.Extra) == 0
+}
+
+// VerifyZoneDNS fetches the DNS records for the zone identifier and compares
+// them with the answers from resolver; see VerifyDNSRecords.
+func (api *API) VerifyZoneDNS(zoneID, resolver string) ([]DNSVerifyResult, error) {
+	records, err := api.DNSRecords(zoneID, DNSRecord{})
+	if err != nil {
+		return nil, err
+	}
+	return VerifyDNSRecords(records, resolver), nil
+}
+
+// VerifyDNSRecords queries resolver, a host with an optional port (default
+// 53), for every name and type in records and reports how the answers differ.
+//
+// This is intended to be run against the previous provider's nameservers
+// before a zone is moved to Cloudflare. Proxied records are compared by their
+// origin content. Records of types that cannot be compared are ignored.
+// Results are sorted by name and type.
+func VerifyDNSRecords(records []DNSRecord, resolver string) []DNSVerifyResult {
+	if _, _, err := net.SplitHostPort(resolver); err != nil {
+		resolver = net.JoinHostPort(resolver, "53")
+	}
+
+	type key struct{ name, rtype string }
+	expected := make(map[key][]string)
+	var keys []key
+	for _, rr := range records {
+		v, ok := verifyValueFromRecord(rr)
+		if !ok {
+			continue
+		}
+		k := key{strings.ToLower(trimDot(rr.Name)), rr.Type}
+		if _, seen := expected[k]; !seen {
+			keys = append(keys, k)
+		}
+		expected[k] = append(expected[k], v)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		return keys[i].rtype < keys[j].rtype
+	})
+
+	var results []DNSVerifyResult
+	for _, k := range keys {
+		res := DNSVerifyResult{Name: k.name, Type: k.rtype, Expected: expected[k]}
+		answers, err := verifyQuery(resolver, k.name, dns.StringToType[k.rtype])
+		if err != nil {
+			res.Err = err
+		} else {
+			res.Missing = stringsDifference(res.Expected, answers)
+			res.Extra = stringsDifference(answers, res.Expected)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// verifyQuery returns the normalised answers of type qtype for name.
+func verifyQuery(resolver, name string, qtype uint16) ([]string, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(name), qtype)
+	c := new(dns.Client)
+	r, _, err := c.Exchange(m, resolver)
+	if err == nil && r.Truncated {
+		c.Net = "tcp"
+		r, _, err = c.Exchange(m, resolver)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "DNS query failed")
+	}
+	switch r.Rcode {
+	case dns.RcodeSuccess, dns.RcodeNameError:
+	default:
+		return nil, errors.Errorf("DNS query failed: %s", dns.RcodeToString[r.Rcode])
+	}
+
+	var answers []string
+	for _, rr := range r.Answer {
+		// Skip the other links of a CNAME chain.
+		if rr.Header().Rrtype != qtype || !strings.EqualFold(rr.Header().Name, dns.Fqdn(name)) {
+			continue
+		}
+		if v, ok := verifyValueFromRR(rr); ok {
+			answers = append(answers, v)
+		}
+	}
+	return answers, nil
+}
+
+// verifyValueFromRecord returns the comparable form of a Cloudflare record.
+func verifyValueFromRecord(rr DNSRecord) (string, bool) {
+	switch rr.Type {
+	case "A", "AAAA":
+		ip := net.ParseIP(rr.Content)
+		if ip == nil {
+			return "", false
+		}
+		return ip.String(), true
+	case "CNAME", "NS", "PTR":
+		return verifyName(rr.Content), true
+	case "MX":
+		return fmt.Sprintf("%d %s", rr.Priority, verifyName(rr.Content)), true
+	case "TXT", "SPF":
+		return rr.Content, true
+	case "SRV":
+		d, ok := rr.Data.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("%v %v %v %s", d["priority"], d["weight"], d["port"], verifyName(fmt.Sprint(d["target"]))), true
+	case "CAA":
+		d, ok := rr.Data.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("%v %v %q", d["flags"], d["tag"], d["value"]), true
+	}
+	return "", false
+}
+
+// verifyValueFromRR returns the comparable form of a resource record.
+func verifyValueFromRR(rr dns.RR) (string, bool) {
+	switch v := rr.(type) {
+	case *dns.A:
+		return v.A.String(), true
+	case *dns.AAAA:
+		return v.AAAA.String(), true
+	case *dns.CNAME:
+		return verifyName(v.Target), true
+	case *dns.NS:
+		return verifyName(v.Ns), true
+	case *dns.PTR:
+		return verifyName(v.Ptr), true
+	case *dns.MX:
+		return fmt.Sprintf("%d %s", v.Preference, verifyName(v.Mx)), true
+	case *dns.TXT:
+		return strings.Join(v.Txt, ""), true
+	case *dns.SPF:
+		return strings.Join(v.Txt, ""), true
+	case *dns.SRV:
+		return fmt.Sprintf("%d %d %d %s", v.Priority, v.Weight, v.Port, verifyName(v.Target)), true
+	case *dns.CAA:
+		return fmt.Sprintf("%d %s %q", v.Flag, v.Tag, v.Value), true
+	}
+	return "", false
+}
+
+// verifyName normalises a domain name for comparison.
+func verifyName(name string) string {
+	return strings.ToLower(trimDot(name))
+}
+
+// stringsDifference returns the values of a that are not in b.
+func stringsDifference(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, s := range b {
+		in[s] = true
+	}
+	var diff []string
+	for _, s := range a {
+		if !in[s] {
+			diff = append(diff, s)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,94 @@
+package cloudflare
+
+import (
+	"net"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+)
+
+// startResolver answers queries over UDP from rrs and returns its address.
+func startResolver(t *testing.T, rrs []string) (string, func()) {
+	var zone []dns.RR
+	for _, s := range rrs {
+		rr, err := dns.NewRR(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zone = append(zone, rr)
+	}
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(r)
+		q := r.Question[0]
+		for _, rr := range zone {
+			if rr.Header().Name == q.Name && rr.Header().Rrtype == q.Qtype {
+				m.Answer = append(m.Answer, rr)
+			}
+		}
+		if q.Name == "servfail.example.com." {
+			m.Rcode = dns.RcodeServerFailure
+		}
+		w.WriteMsg(m)
+	})
+	server := &dns.Server{PacketConn: pc, Handler: handler}
+	go server.ActivateAndServe()
+	return pc.LocalAddr().String(), func() { server.Shutdown() }
+}
+
+func TestVerifyDNSRecords(t *testing.T) {
+	addr, stop := startResolver(t, []string{
+		"example.com. 300 IN A 192.0.2.1",
+		"example.com. 300 IN A 192.0.2.9",
+		"example.com. 300 IN MX 10 Mail.example.com.",
+		"www.example.com. 300 IN CNAME example.com.",
+		"_sip._tcp.example.com. 300 IN SRV 10 20 5060 sip.example.com.",
+	})
+	defer stop()
+
+	records := []DNSRecord{
+		{Type: "A", Name: "example.com", Content: "192.0.2.1", Proxied: true},
+		{Type: "A", Name: "example.com", Content: "192.0.2.2"},
+		{Type: "MX", Name: "example.com", Content: "mail.example.com", Priority: 10},
+		{Type: "CNAME", Name: "www.example.com", Content: "example.com"},
+		{Type: "SRV", Name: "_sip._tcp.example.com", Data: map[string]interface{}{
+			"priority": 10.0, "weight": 20.0, "port": 5060.0, "target": "sip.example.com",
+		}},
+		{Type: "TXT", Name: "missing.example.com", Content: "hello"},
+		{Type: "A", Name: "servfail.example.com", Content: "192.0.2.3"},
+		{Type: "LOC", Name: "example.com", Content: "ignored"},
+	}
+
+	results := VerifyDNSRecords(records, addr)
+	if !assert.Len(t, results, 6) {
+		return
+	}
+
+	assert.Equal(t, "_sip._tcp.example.com", results[0].Name)
+	assert.True(t, results[0].OK())
+
+	assert.Equal(t, "example.com", results[1].Name)
+	assert.Equal(t, "A", results[1].Type)
+	assert.Equal(t, []string{"192.0.2.2"}, results[1].Missing)
+	assert.Equal(t, []string{"192.0.2.9"}, results[1].Extra)
+
+	assert.Equal(t, "MX", results[2].Type)
+	assert.True(t, results[2].OK())
+
+	assert.Equal(t, "missing.example.com", results[3].Name)
+	assert.Equal(t, []string{"hello"}, results[3].Missing)
+	assert.Empty(t, results[3].Extra)
+
+	assert.Equal(t, "servfail.example.com", results[4].Name)
+	assert.Error(t, results[4].Err)
+	assert.False(t, results[4].OK())
+
+	assert.Equal(t, "www.example.com", results[5].Name)
+	assert.True(t, results[5].OK())
+}
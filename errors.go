@@ -1,5 +1,12 @@
 package cloudflare
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
 // Error messages
 const (
 	errEmptyCredentials     = "invalid credentials: key & email must not be empty"
@@ -46,3 +53,19 @@ func (e *UserError) Parse() bool {
 func (e *UserError) Error() string {
 	return e.Err.Error()
 }
+
+// responseError returns an error describing the errors reported in r, or nil
+// if the request was successful.
+func responseError(r Response) error {
+	if r.Success {
+		return nil
+	}
+	if len(r.Errors) == 0 {
+		return errors.New(errRequestNotSuccessful)
+	}
+	msgs := make([]string, len(r.Errors))
+	for i, e := range r.Errors {
+		msgs[i] = fmt.Sprintf("%s (%d)", e.Message, e.Code)
+	}
+	return errors.Errorf("%s: %s", errRequestNotSuccessful, strings.Join(msgs, "; "))
+}
@@ -0,0 +1,180 @@
+package cloudflare
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Filter is an expression in the firewall rules language, such as
+// `http.request.uri.path contains "/admin"`, used by firewall rules to match
+// requests.
+type Filter struct {
+	ID          string `json:"id,omitempty"`
+	Expression  string `json:"expression,omitempty"`
+	Paused      bool   `json:"paused"`
+	Description string `json:"description,omitempty"`
+	// Ref is a short reference, such as an issue number, for your own use.
+	Ref string `json:"ref,omitempty"`
+}
+
+// FilterResponse represents the response from the filter endpoint for a
+// single filter.
+type FilterResponse struct {
+	Response
+	Result Filter `json:"result"`
+}
+
+// FiltersResponse represents the response from the filters endpoint.
+type FiltersResponse struct {
+	Response
+	Result     []Filter   `json:"result"`
+	ResultInfo ResultInfo `json:"result_info"`
+}
+
+// ListFilters returns all filters for a zone.
+//
+// API reference: https://api.cloudflare.com/#filters-list-filters
+func (api *API) ListFilters(zoneID string) ([]Filter, error) {
+	v := url.Values{}
+	v.Set("per_page", "100")
+
+	var filters []Filter
+	page := 1
+	for {
+		v.Set("page", strconv.Itoa(page))
+		uri := "/zones/" + zoneID + "/filters?" + v.Encode()
+		res, err := api.makeRequest("GET", uri, nil)
+		if err != nil {
+			return []Filter{}, errors.Wrap(err, errMakeRequestError)
+		}
+		var r FiltersResponse
+		err = json.Unmarshal(res, &r)
+		if err != nil {
+			return []Filter{}, errors.Wrap(err, errUnmarshalError)
+		}
+		if err := responseError(r.Response); err != nil {
+			return []Filter{}, err
+		}
+		filters = append(filters, r.Result...)
+		if r.ResultInfo.Page >= r.ResultInfo.TotalPages {
+			break
+		}
+		page++
+	}
+	return filters, nil
+}
+
+// Filter returns a single filter.
+//
+// API reference: https://api.cloudflare.com/#filters-get-by-filter-id
+func (api *API) Filter(zoneID, filterID string) (Filter, error) {
+	uri := "/zones/" + zoneID + "/filters/" + filterID
+	res, err := api.makeRequest("GET", uri, nil)
+	if err != nil {
+		return Filter{}, errors.Wrap(err, errMakeRequestError)
+	}
+	var r FilterResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return Filter{}, errors.Wrap(err, errUnmarshalError)
+	}
+	if err := responseError(r.Response); err != nil {
+		return Filter{}, err
+	}
+	return r.Result, nil
+}
+
+// CreateFilters creates filters for a zone in a single request and returns
+// them with their IDs.
+//
+// API reference: https://api.cloudflare.com/#filters-create-filters
+func (api *API) CreateFilters(zoneID string, filters []Filter) ([]Filter, error) {
+	uri := "/zones/" + zoneID + "/filters"
+	return api.filtersRequest("POST", uri, filters)
+}
+
+// UpdateFilters replaces filters in a single request. Each filter must have
+// an ID.
+//
+// API reference: https://api.cloudflare.com/#filters-update-filters
+func (api *API) UpdateFilters(zoneID string, filters []Filter) ([]Filter, error) {
+	for _, f := range filters {
+		if f.ID == "" {
+			return []Filter{}, errors.New("filter ID is required to update a filter")
+		}
+	}
+	uri := "/zones/" + zoneID + "/filters"
+	return api.filtersRequest("PUT", uri, filters)
+}
+
+// DeleteFilters deletes filters in a single request. Filters still used by a
+// firewall rule can't be deleted.
+//
+// API reference: https://api.cloudflare.com/#filters-delete-filters
+func (api *API) DeleteFilters(zoneID string, filterIDs []string) error {
+	v := url.Values{}
+	for _, id := range filterIDs {
+		v.Add("id", id)
+	}
+	uri := "/zones/" + zoneID + "/filters?" + v.Encode()
+	_, err := api.filtersRequest("DELETE", uri, nil)
+	return err
+}
+
+// filtersRequest makes a request to the filters endpoint returning a list of
+// filters.
+func (api *API) filtersRequest(method, uri string, params interface{}) ([]Filter, error) {
+	res, err := api.makeRequest(method, uri, params)
+	if err != nil {
+		return []Filter{}, errors.Wrap(err, errMakeRequestError)
+	}
+	var r FiltersResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return []Filter{}, errors.Wrap(err, errUnmarshalError)
+	}
+	if err := responseError(r.Response); err != nil {
+		return []Filter{}, err
+	}
+	return r.Result, nil
+}
+
+// ValidateFilterExpression checks the syntax of a filter expression without
+// creating a filter. It returns nil if the expression is valid, or an error
+// containing the API's description of the problem.
+//
+// API reference: https://api.cloudflare.com/#filters-validate-expression
+func (api *API) ValidateFilterExpression(expression string) error {
+	params, err := json.Marshal(struct {
+		Expression string `json:"expression"`
+	}{expression})
+	if err != nil {
+		return errors.Wrap(err, "error marshalling params to JSON")
+	}
+	// An invalid expression is reported with a 400 status, so the response
+	// is read here rather than by makeRequest, which would discard it.
+	resp, err := api.request("POST", "/filters/validate-expr", bytes.NewReader(params), api.authType)
+	if err != nil {
+		return errors.Wrap(err, errMakeRequestError)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "could not read response body")
+	}
+
+	var r Response
+	if err := json.Unmarshal(body, &r); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return errors.Errorf("HTTP status %d: content %q", resp.StatusCode, string(body))
+		}
+		return errors.Wrap(err, errUnmarshalError)
+	}
+	return responseError(r)
+}
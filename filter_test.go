@@ -0,0 +1,68 @@
+package cloudflare
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateFilters(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/filters", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method, "Expected method 'POST', got %s", r.Method)
+		b, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `[{"expression":"ip.src eq 198.51.100.4","paused":false,"ref":"SEC-1"}]`, string(b))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": [
+			{"id": "f1", "expression": "ip.src eq 198.51.100.4", "paused": false, "ref": "SEC-1"}
+		]}`)
+	})
+
+	filters, err := client.CreateFilters("abc", []Filter{{Expression: "ip.src eq 198.51.100.4", Ref: "SEC-1"}})
+	if assert.NoError(t, err) && assert.Len(t, filters, 1) {
+		assert.Equal(t, "f1", filters[0].ID)
+	}
+}
+
+func TestDeleteFilters(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/filters", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method, "Expected method 'DELETE', got %s", r.Method)
+		assert.Equal(t, []string{"f1", "f2"}, r.URL.Query()["id"])
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": [{"id": "f1"}, {"id": "f2"}]}`)
+	})
+
+	assert.NoError(t, client.DeleteFilters("abc", []string{"f1", "f2"}))
+}
+
+func TestValidateFilterExpression(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/filters/validate-expr", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method, "Expected method 'POST', got %s", r.Method)
+		b, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("content-type", "application/json")
+		if string(b) == `{"expression":"ip.src eq 198.51.100.4"}` {
+			fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": null}`)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"success": false, "errors": [{"code": 10014, "message": "Filter parsing error: unknown identifier"}], "messages": [], "result": null}`)
+	})
+
+	assert.NoError(t, client.ValidateFilterExpression("ip.src eq 198.51.100.4"))
+
+	err := client.ValidateFilterExpression("ip.sauce eq 198.51.100.4")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Filter parsing error: unknown identifier")
+	}
+}
@@ -0,0 +1,272 @@
+package filterexpr
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Request is a synthetic request to evaluate expressions against. Fields
+// left empty simply fail to match.
+type Request struct {
+	Method string
+	// Scheme is http or https.
+	Scheme string
+	Host   string
+	// URI is the path and query string, such as /search?q=cloudflare.
+	URI string
+	// Version is the HTTP version, such as HTTP/1.1.
+	Version string
+	Headers http.Header
+
+	IP net.IP
+	// Country is the two-letter country code of IP, and Continent the
+	// two-letter continent code, such as EU.
+	Country   string
+	Continent string
+	ASN       int
+
+	ThreatScore int
+	// Bot is true for requests from known good bots.
+	Bot bool
+}
+
+// NewRequest returns a request for method and an absolute URL, using
+// HTTP/1.1 and empty headers.
+func NewRequest(method, rawurl string) (*Request, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return &Request{
+		Method:  method,
+		Scheme:  u.Scheme,
+		Host:    u.Host,
+		URI:     u.RequestURI(),
+		Version: "HTTP/1.1",
+		Headers: make(http.Header),
+	}, nil
+}
+
+// Match reports whether the expression matches r.
+func (e *Expression) Match(r *Request) bool {
+	return e.root.eval(r)
+}
+
+type fieldType int
+
+const (
+	typeString fieldType = iota
+	typeInt
+	typeIP
+	typeBool
+)
+
+func (t fieldType) String() string {
+	switch t {
+	case typeString:
+		return "string"
+	case typeInt:
+		return "integer"
+	case typeIP:
+		return "IP address"
+	}
+	return "boolean"
+}
+
+// field is a field that can be used in an expression, and how to find its
+// value in a request.
+type field struct {
+	name string
+	typ  fieldType
+	get  func(r *Request) interface{}
+}
+
+// headersField is the field holding the request headers. It is indexed by
+// header name, as in http.request.headers["x-forwarded-proto"].
+const headersField = "http.request.headers"
+
+// headerField returns the field for the first value of a request header.
+func headerField(name string) field {
+	return field{
+		name: headersField + "[" + name + "]",
+		typ:  typeString,
+		get:  func(r *Request) interface{} { return r.Headers.Get(name) },
+	}
+}
+
+// uriPart returns the path (0) or query string (1) of r.URI.
+func uriPart(r *Request, part int) string {
+	parts := strings.SplitN(r.URI, "?", 2)
+	if part < len(parts) {
+		return parts[part]
+	}
+	return ""
+}
+
+// fields holds the fields supported, by name.
+var fields = map[string]field{}
+
+func init() {
+	for _, f := range []field{
+		{"http.cookie", typeString, func(r *Request) interface{} { return r.Headers.Get("Cookie") }},
+		{"http.host", typeString, func(r *Request) interface{} { return r.Host }},
+		{"http.referer", typeString, func(r *Request) interface{} { return r.Headers.Get("Referer") }},
+		{"http.request.full_uri", typeString, func(r *Request) interface{} { return r.Scheme + "://" + r.Host + r.URI }},
+		{"http.request.method", typeString, func(r *Request) interface{} { return r.Method }},
+		{"http.request.uri", typeString, func(r *Request) interface{} { return r.URI }},
+		{"http.request.uri.path", typeString, func(r *Request) interface{} { return uriPart(r, 0) }},
+		{"http.request.uri.query", typeString, func(r *Request) interface{} { return uriPart(r, 1) }},
+		{"http.request.version", typeString, func(r *Request) interface{} { return r.Version }},
+		{"http.user_agent", typeString, func(r *Request) interface{} { return r.Headers.Get("User-Agent") }},
+		{"http.x_forwarded_for", typeString, func(r *Request) interface{} { return r.Headers.Get("X-Forwarded-For") }},
+		{"ip.src", typeIP, func(r *Request) interface{} { return r.IP }},
+		{"ip.geoip.asnum", typeInt, func(r *Request) interface{} { return int64(r.ASN) }},
+		{"ip.geoip.continent", typeString, func(r *Request) interface{} { return r.Continent }},
+		{"ip.geoip.country", typeString, func(r *Request) interface{} { return r.Country }},
+		{"cf.threat_score", typeInt, func(r *Request) interface{} { return int64(r.ThreatScore) }},
+		{"cf.client.bot", typeBool, func(r *Request) interface{} { return r.Bot }},
+		{"ssl", typeBool, func(r *Request) interface{} { return r.Scheme == "https" }},
+	} {
+		fields[f.name] = f
+	}
+}
+
+type node interface {
+	eval(r *Request) bool
+}
+
+type orNode struct{ left, right node }
+
+func (n orNode) eval(r *Request) bool { return n.left.eval(r) || n.right.eval(r) }
+
+type xorNode struct{ left, right node }
+
+func (n xorNode) eval(r *Request) bool { return n.left.eval(r) != n.right.eval(r) }
+
+type andNode struct{ left, right node }
+
+func (n andNode) eval(r *Request) bool { return n.left.eval(r) && n.right.eval(r) }
+
+type notNode struct{ x node }
+
+func (n notNode) eval(r *Request) bool { return !n.x.eval(r) }
+
+type boolNode struct{ field field }
+
+func (n boolNode) eval(r *Request) bool { return n.field.get(r).(bool) }
+
+// set is the set of values on the right of the in operator.
+type set struct {
+	strings []string
+	ints    [][2]int64
+	ips     []net.IP
+	nets    []*net.IPNet
+}
+
+func (s set) empty() bool {
+	return len(s.strings) == 0 && len(s.ints) == 0 && len(s.ips) == 0 && len(s.nets) == 0
+}
+
+// compareNode compares a field with a value, or tests whether it is in a
+// set.
+type compareNode struct {
+	field field
+	op    string
+	value interface{}
+	set   set
+}
+
+func (n compareNode) eval(r *Request) bool {
+	v := n.field.get(r)
+	switch n.field.typ {
+	case typeString:
+		return n.evalString(v.(string))
+	case typeInt:
+		return n.evalInt(v.(int64))
+	case typeIP:
+		return n.evalIP(v.(net.IP))
+	}
+	return false
+}
+
+func (n compareNode) evalString(s string) bool {
+	switch n.op {
+	case "in":
+		for _, m := range n.set.strings {
+			if s == m {
+				return true
+			}
+		}
+		return false
+	case "matches":
+		return n.value.(*regexp.Regexp).MatchString(s)
+	case "contains":
+		return strings.Contains(s, n.value.(string))
+	}
+	return compare(n.op, strings.Compare(s, n.value.(string)))
+}
+
+func (n compareNode) evalInt(i int64) bool {
+	if n.op == "in" {
+		for _, r := range n.set.ints {
+			if i >= r[0] && i <= r[1] {
+				return true
+			}
+		}
+		return false
+	}
+	v := n.value.(int64)
+	c := 0
+	if i < v {
+		c = -1
+	} else if i > v {
+		c = 1
+	}
+	return compare(n.op, c)
+}
+
+func (n compareNode) evalIP(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	switch n.op {
+	case "in":
+		for _, m := range n.set.ips {
+			if ip.Equal(m) {
+				return true
+			}
+		}
+		for _, m := range n.set.nets {
+			if m.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	case "ne":
+		return !ip.Equal(n.value.(net.IP))
+	}
+	return ip.Equal(n.value.(net.IP))
+}
+
+// compare applies an ordering operator to the result c of comparing two
+// values, which is negative, zero or positive.
+func compare(op string, c int) bool {
+	switch op {
+	case "eq":
+		return c == 0
+	case "ne":
+		return c != 0
+	case "lt":
+		return c < 0
+	case "le":
+		return c <= 0
+	case "gt":
+		return c > 0
+	case "ge":
+		return c >= 0
+	}
+	return false
+}
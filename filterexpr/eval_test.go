@@ -0,0 +1,76 @@
+package filterexpr
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRequest(t *testing.T) *Request {
+	r, err := NewRequest("POST", "https://www.example.com/admin/login?next=%2F")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.IP = net.ParseIP("198.51.100.4")
+	r.Country = "GB"
+	r.Continent = "EU"
+	r.ASN = 64500
+	r.ThreatScore = 14
+	r.Headers.Set("User-Agent", "curl/7.54.0")
+	r.Headers.Set("X-Debug", "1")
+	return r
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		expr  string
+		match bool
+	}{
+		{`http.request.uri.path contains "/admin"`, true},
+		{`http.request.uri.path eq "/admin/login"`, true},
+		{`http.request.uri.query eq "next=%2F"`, true},
+		{`http.request.uri eq "/admin/login?next=%2F"`, true},
+		{`http.request.full_uri eq "https://www.example.com/admin/login?next=%2F"`, true},
+		{`http.host in {"example.com" "www.example.com"}`, true},
+		{`http.request.method in {"GET" "HEAD"}`, false},
+		{`http.request.version eq "HTTP/1.1"`, true},
+		{`http.user_agent matches "^curl/"`, true},
+		{`http.user_agent ~ "^Mozilla"`, false},
+		{`http.request.headers["x-debug"] eq "1"`, true},
+		{`http.request.headers["x-missing"] ne ""`, false},
+		{`ip.src eq 198.51.100.4`, true},
+		{`ip.src ne 198.51.100.4`, false},
+		{`ip.src in {198.51.100.0/24}`, true},
+		{`ip.src in {192.0.2.0/24 2001:db8::1}`, false},
+		{`ip.geoip.country eq "GB" and ip.geoip.continent eq "EU"`, true},
+		{`ip.geoip.asnum in {64496..64511}`, true},
+		{`ip.geoip.asnum in {13335}`, false},
+		{`cf.threat_score gt 10`, true},
+		{`cf.threat_score le 10`, false},
+		{`ssl`, true},
+		{`not ssl`, false},
+		{`cf.client.bot`, false},
+		{`ssl xor cf.client.bot`, true},
+		{`ssl ^^ ip.geoip.country eq "GB"`, false},
+		{`not ssl or ip.geoip.country eq "GB" and cf.threat_score gt 10`, true},
+		{`(not ssl or ip.geoip.country eq "US") and cf.threat_score gt 10`, false},
+		{`http.request.uri.path contains "/admin" and not ip.src in {198.51.100.0/24}`, false},
+		{`http.host lt "www.example.net"`, true},
+	}
+	r := testRequest(t)
+	for _, tt := range tests {
+		e, err := Parse(tt.expr)
+		if assert.NoError(t, err, tt.expr) {
+			assert.Equal(t, tt.match, e.Match(r), tt.expr)
+		}
+	}
+}
+
+func TestMatchEmptyRequest(t *testing.T) {
+	r := &Request{}
+	assert.False(t, MustParse(`ip.src eq 198.51.100.4`).Match(r))
+	assert.False(t, MustParse(`ip.src ne 198.51.100.4`).Match(r))
+	assert.False(t, MustParse(`http.user_agent contains "curl"`).Match(r))
+	assert.True(t, MustParse(`http.request.headers["x-debug"] eq ""`).Match(r))
+}
@@ -0,0 +1,449 @@
+// Package filterexpr parses and evaluates firewall rule filter expressions
+// offline, so that rules can be tested without a live zone.
+//
+// The expression language is the Wireshark-like language used by firewall
+// rules:
+//
+//	http.request.uri.path contains "/admin" and not ip.src in {198.51.100.0/24}
+//
+// Comparisons are written as a field, an operator and a value. The operators
+// are eq (==), ne (!=), lt (<), le (<=), gt (>), ge (>=), contains, matches
+// (~) and in, which tests membership of a set such as {"GET" "HEAD"},
+// {80 443 8000..8999} or {192.0.2.1 198.51.100.0/24}. Boolean fields such as
+// ssl are used on their own. Comparisons are combined with not (!), and (&&),
+// xor (^^) and or (||), in decreasing order of precedence, and grouped with
+// parentheses.
+package filterexpr
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SyntaxError describes a problem with an expression and where it is.
+type SyntaxError struct {
+	// Offset is the byte offset of the problem in the expression.
+	Offset int
+	// Line and Column give the position of the problem, counting from 1.
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Msg)
+}
+
+// newSyntaxError returns a SyntaxError at byte offset pos of src.
+func newSyntaxError(src string, pos int, format string, args ...interface{}) *SyntaxError {
+	line := 1 + strings.Count(src[:pos], "\n")
+	col := pos - strings.LastIndex(src[:pos], "\n")
+	return &SyntaxError{Offset: pos, Line: line, Column: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	// tokWord is a field name, keyword, number, IP address or range.
+	tokWord
+	tokString
+	// tokPunct is a bracket or symbolic operator.
+	tokPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// punctuation lists the symbols of the language, longest first.
+var punctuation = []string{
+	"==", "!=", "<=", ">=", "&&", "||", "^^",
+	"(", ")", "{", "}", "[", "]", "<", ">", "~", "!",
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '.' || c == ':' || c == '/'
+}
+
+// lex splits src into tokens.
+func lex(src string) ([]token, error) {
+	var toks []token
+	i := 0
+next:
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case c == '"':
+			start := i
+			var b strings.Builder
+			for i++; i < len(src); i++ {
+				switch src[i] {
+				case '\\':
+					if i+1 < len(src) {
+						i++
+						b.WriteByte(src[i])
+					}
+				case '"':
+					i++
+					toks = append(toks, token{tokString, b.String(), start})
+					continue next
+				default:
+					b.WriteByte(src[i])
+				}
+			}
+			return nil, newSyntaxError(src, start, "unterminated string")
+		case isWordByte(c):
+			start := i
+			for i < len(src) && isWordByte(src[i]) {
+				i++
+			}
+			toks = append(toks, token{tokWord, src[start:i], start})
+			continue
+		}
+		for _, p := range punctuation {
+			if strings.HasPrefix(src[i:], p) {
+				toks = append(toks, token{tokPunct, p, i})
+				i += len(p)
+				continue next
+			}
+		}
+		return nil, newSyntaxError(src, i, "unexpected character %q", c)
+	}
+	return append(toks, token{tokEOF, "", len(src)}), nil
+}
+
+// operators maps the symbolic comparison operators to their names.
+var operators = map[string]string{
+	"eq": "eq", "==": "eq",
+	"ne": "ne", "!=": "ne",
+	"lt": "lt", "<": "lt",
+	"le": "le", "<=": "le",
+	"gt": "gt", ">": "gt",
+	"ge": "ge", ">=": "ge",
+	"contains": "contains",
+	"matches":  "matches", "~": "matches",
+	"in": "in",
+}
+
+// operatorsByType lists the comparison operators allowed for each type.
+var operatorsByType = map[fieldType]map[string]bool{
+	typeString: {"eq": true, "ne": true, "lt": true, "le": true, "gt": true, "ge": true,
+		"contains": true, "matches": true, "in": true},
+	typeInt: {"eq": true, "ne": true, "lt": true, "le": true, "gt": true, "ge": true, "in": true},
+	typeIP:  {"eq": true, "ne": true, "in": true},
+}
+
+// Expression is a parsed filter expression.
+type Expression struct {
+	src  string
+	root node
+}
+
+// Parse parses a filter expression. Errors are of type *SyntaxError.
+func Parse(src string) (*Expression, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: src, toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %q", t.text)
+	}
+	return &Expression{src: src, root: root}, nil
+}
+
+// MustParse is like Parse but panics if the expression can't be parsed. It
+// simplifies initialising tables of expressions in tests.
+func MustParse(src string) *Expression {
+	e, err := Parse(src)
+	if err != nil {
+		panic(fmt.Sprintf("filterexpr: Parse(%q): %s", src, err))
+	}
+	return e
+}
+
+// String returns the source of the expression.
+func (e *Expression) String() string {
+	return e.src
+}
+
+type parser struct {
+	src  string
+	toks []token
+	i    int
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// accept consumes the next token if it is one of words or symbols.
+func (p *parser) accept(alternatives ...string) bool {
+	t := p.peek()
+	if t.kind != tokWord && t.kind != tokPunct {
+		return false
+	}
+	for _, a := range alternatives {
+		if t.text == a {
+			p.i++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	if t.kind == tokEOF {
+		return newSyntaxError(p.src, t.pos, "unexpected end of expression")
+	}
+	return newSyntaxError(p.src, t.pos, format, args...)
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseXor()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("or", "||") {
+		right, err := p.parseXor()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseXor() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("xor", "^^") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = xorNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("and", "&&") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.accept("not", "!") {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	if t.kind == tokPunct && t.text == "(" {
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokPunct || t.text != ")" {
+			return nil, p.errorf(t, "expected ) but found %q", t.text)
+		}
+		return x, nil
+	}
+	if t.kind != tokWord {
+		return nil, p.errorf(t, "expected a field or ( but found %q", t.text)
+	}
+
+	f, err := p.parseField(t)
+	if err != nil {
+		return nil, err
+	}
+	opTok := p.peek()
+	op, isOp := operators[opTok.text]
+	if opTok.kind == tokString || opTok.kind == tokEOF || !isOp {
+		if f.typ == typeBool {
+			return boolNode{f}, nil
+		}
+		return nil, p.errorf(opTok, "expected an operator after %s but found %q", f.name, opTok.text)
+	}
+	p.next()
+	if f.typ == typeBool {
+		return nil, p.errorf(opTok, "%s is a boolean field and can't be compared", f.name)
+	}
+	if !operatorsByType[f.typ][op] {
+		return nil, p.errorf(opTok, "operator %s can't be used with %s field %s", opTok.text, f.typ, f.name)
+	}
+
+	c := compareNode{field: f, op: op}
+	if op == "in" {
+		c.set, err = p.parseSet(f)
+	} else {
+		c.value, err = p.parseValue(f, op)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// parseField looks up the field named by t, including the header name of
+// http.request.headers["name"].
+func (p *parser) parseField(t token) (field, error) {
+	if t.text == headersField {
+		if !p.accept("[") {
+			return field{}, p.errorf(p.peek(), "expected [ after %s", headersField)
+		}
+		nameTok := p.next()
+		if nameTok.kind != tokString {
+			return field{}, p.errorf(nameTok, "expected a header name string but found %q", nameTok.text)
+		}
+		if !p.accept("]") {
+			return field{}, p.errorf(p.peek(), "expected ] after header name")
+		}
+		return headerField(nameTok.text), nil
+	}
+	f, ok := fields[t.text]
+	if !ok {
+		return field{}, p.errorf(t, "unknown field %q", t.text)
+	}
+	return f, nil
+}
+
+// parseValue parses the value compared with field f by op.
+func (p *parser) parseValue(f field, op string) (interface{}, error) {
+	t := p.next()
+	switch f.typ {
+	case typeString:
+		if t.kind != tokString {
+			return nil, p.errorf(t, "expected a string to compare with %s but found %q", f.name, t.text)
+		}
+		if op == "matches" {
+			re, err := regexp.Compile(t.text)
+			if err != nil {
+				return nil, p.errorf(t, "invalid regular expression: %s", err)
+			}
+			return re, nil
+		}
+		return t.text, nil
+	case typeInt:
+		n, err := strconv.ParseInt(t.text, 10, 64)
+		if t.kind != tokWord || err != nil {
+			return nil, p.errorf(t, "expected an integer to compare with %s but found %q", f.name, t.text)
+		}
+		return n, nil
+	case typeIP:
+		ip := net.ParseIP(t.text)
+		if t.kind != tokWord || ip == nil {
+			if _, _, err := net.ParseCIDR(t.text); err == nil {
+				return nil, p.errorf(t, "use in to compare %s with the range %s", f.name, t.text)
+			}
+			return nil, p.errorf(t, "expected an IP address to compare with %s but found %q", f.name, t.text)
+		}
+		return ip, nil
+	}
+	return nil, p.errorf(t, "can't compare %s", f.name)
+}
+
+// parseSet parses a set of values of the type of field f, such as
+// {"a" "b"}, {80 8000..8999} or {192.0.2.1 198.51.100.0/24}.
+func (p *parser) parseSet(f field) (set, error) {
+	var s set
+	open := p.next()
+	if open.kind != tokPunct || open.text != "{" {
+		return s, p.errorf(open, "expected { after in but found %q", open.text)
+	}
+	for {
+		t := p.next()
+		if t.kind == tokPunct && t.text == "}" {
+			break
+		}
+		if t.kind == tokEOF {
+			return s, newSyntaxError(p.src, open.pos, "unterminated set")
+		}
+		switch f.typ {
+		case typeString:
+			if t.kind != tokString {
+				return s, p.errorf(t, "expected a string in set for %s but found %q", f.name, t.text)
+			}
+			s.strings = append(s.strings, t.text)
+		case typeInt:
+			r, ok := parseIntRange(t)
+			if !ok {
+				return s, p.errorf(t, "expected an integer or range in set for %s but found %q", f.name, t.text)
+			}
+			s.ints = append(s.ints, r)
+		case typeIP:
+			if t.kind == tokWord {
+				if ip := net.ParseIP(t.text); ip != nil {
+					s.ips = append(s.ips, ip)
+					continue
+				}
+				if _, n, err := net.ParseCIDR(t.text); err == nil {
+					s.nets = append(s.nets, n)
+					continue
+				}
+			}
+			return s, p.errorf(t, "expected an IP address or range in set for %s but found %q", f.name, t.text)
+		}
+	}
+	if s.empty() {
+		return s, newSyntaxError(p.src, open.pos, "empty set")
+	}
+	return s, nil
+}
+
+// parseIntRange parses an integer or an inclusive range such as 8000..8999.
+func parseIntRange(t token) ([2]int64, bool) {
+	if t.kind != tokWord {
+		return [2]int64{}, false
+	}
+	lo, hi := t.text, t.text
+	if i := strings.Index(t.text, ".."); i >= 0 {
+		lo, hi = t.text[:i], t.text[i+2:]
+	}
+	l, err1 := strconv.ParseInt(lo, 10, 64)
+	h, err2 := strconv.ParseInt(hi, 10, 64)
+	if err1 != nil || err2 != nil || l > h {
+		return [2]int64{}, false
+	}
+	return [2]int64{l, h}, true
+}
@@ -0,0 +1,61 @@
+package filterexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	valid := []string{
+		`http.request.uri.path contains "/admin" and ip.src in {1.2.3.0/24}`,
+		`(http.host eq "example.com" || http.host == "www.example.com") && not ssl`,
+		`ip.geoip.asnum in {13335 64496..64511} xor cf.client.bot`,
+		`http.user_agent ~ "(?i)curl/" or http.request.headers["X-Debug"] ne ""`,
+		`ip.src in {2001:db8::/32 192.0.2.1} and cf.threat_score ge 10`,
+		`!(http.request.method in {"GET" "HEAD"})`,
+		"http.request.uri.query contains \"a=\\\"b\\\"\"\n\tand ip.geoip.country eq \"GB\"",
+	}
+	for _, src := range valid {
+		e, err := Parse(src)
+		if assert.NoError(t, err, src) {
+			assert.Equal(t, src, e.String())
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src    string
+		line   int
+		column int
+		msg    string
+	}{
+		{`http.request.uri.paht eq "/"`, 1, 1, `unknown field "http.request.uri.paht"`},
+		{`ip.src eq 1.2.3.0/24`, 1, 11, "use in to compare ip.src with the range 1.2.3.0/24"},
+		{`ip.src contains "1.2"`, 1, 8, "operator contains can't be used with IP address field ip.src"},
+		{`http.host eq example.com`, 1, 14, `expected a string to compare with http.host but found "example.com"`},
+		{`http.host eq "example.com`, 1, 14, "unterminated string"},
+		{`ip.src in {1.2.3.4`, 1, 11, "unterminated set"},
+		{`ip.src in {}`, 1, 11, "empty set"},
+		{`cf.threat_score gt 10 and`, 1, 26, "unexpected end of expression"},
+		{`(ssl and cf.client.bot`, 1, 23, "unexpected end of expression"},
+		{`ssl eq 1`, 1, 5, "ssl is a boolean field and can't be compared"},
+		{"ssl and\nhttp.host", 2, 10, "unexpected end of expression"},
+		{"ssl and\n  http.user_agent matches \"(\"", 2, 27, "invalid regular expression: error parsing regexp: missing closing ): `(`"},
+		{`ssl ssl`, 1, 5, `unexpected "ssl"`},
+		{`ssl # comment`, 1, 5, `unexpected character '#'`},
+		{`ip.geoip.asnum in {10..1}`, 1, 20, `expected an integer or range in set for ip.geoip.asnum but found "10..1"`},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.src)
+		if assert.Error(t, err, tt.src) {
+			se, ok := err.(*SyntaxError)
+			if assert.True(t, ok, tt.src) {
+				assert.Equal(t, tt.line, se.Line, tt.src)
+				assert.Equal(t, tt.column, se.Column, tt.src)
+				assert.Equal(t, tt.msg, se.Msg, tt.src)
+			}
+		}
+	}
+}
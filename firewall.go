@@ -0,0 +1,293 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// AccessRule represents a firewall access rule, which blocks, challenges or
+// whitelists requests by IP address, IP range, ASN or country.
+//
+// Mode can be one of block, challenge, whitelist or js_challenge.
+type AccessRule struct {
+	ID            string                  `json:"id,omitempty"`
+	Notes         string                  `json:"notes,omitempty"`
+	AllowedModes  []string                `json:"allowed_modes,omitempty"`
+	Mode          string                  `json:"mode,omitempty"`
+	Configuration AccessRuleConfiguration `json:"configuration"`
+	Scope         *AccessRuleScope        `json:"scope,omitempty"`
+	CreatedOn     *time.Time              `json:"created_on,omitempty"`
+	ModifiedOn    *time.Time              `json:"modified_on,omitempty"`
+}
+
+// AccessRuleConfiguration is the request matched by an access rule.
+//
+// Target can be one of ip, ip_range, asn or country. Value is an IPv4 or
+// IPv6 address, a CIDR range (/16 or /24 for IPv4, /32, /48 or /64 for IPv6),
+// an AS number such as AS13335 or a two-letter country code.
+type AccessRuleConfiguration struct {
+	Target string `json:"target,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+// AccessRuleScope is the owner of an access rule: a zone, a user or an
+// organization.
+type AccessRuleScope struct {
+	ID    string `json:"id,omitempty"`
+	Email string `json:"email,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Type  string `json:"type,omitempty"`
+}
+
+// AccessRuleResponse represents the response from the firewall access rule
+// endpoint for a single rule.
+type AccessRuleResponse struct {
+	Response
+	Result AccessRule `json:"result"`
+}
+
+// AccessRuleListResponse represents the response from the firewall access
+// rules endpoint.
+type AccessRuleListResponse struct {
+	Response
+	Result     []AccessRule `json:"result"`
+	ResultInfo ResultInfo   `json:"result_info"`
+}
+
+// Access rule modes.
+const (
+	AccessRuleBlock       = "block"
+	AccessRuleChallenge   = "challenge"
+	AccessRuleWhitelist   = "whitelist"
+	AccessRuleJSChallenge = "js_challenge"
+)
+
+// validAccessRuleModes and validAccessRuleTargets hold the modes and
+// configuration targets accepted by the API.
+var (
+	validAccessRuleModes = map[string]bool{
+		AccessRuleBlock:       true,
+		AccessRuleChallenge:   true,
+		AccessRuleWhitelist:   true,
+		AccessRuleJSChallenge: true,
+	}
+	validAccessRuleTargets = map[string]bool{
+		"ip":       true,
+		"ip_range": true,
+		"asn":      true,
+		"country":  true,
+	}
+)
+
+// Validate checks that the mode and configuration of an access rule are
+// valid. Empty fields are allowed, as they are when changing a rule.
+func (r AccessRule) Validate() error {
+	if r.Mode != "" && !validAccessRuleModes[r.Mode] {
+		return errors.Errorf("invalid access rule mode %q", r.Mode)
+	}
+	if r.Configuration.Target != "" && !validAccessRuleTargets[r.Configuration.Target] {
+		return errors.Errorf("invalid access rule target %q", r.Configuration.Target)
+	}
+	return nil
+}
+
+// ListZoneAccessRules returns the firewall access rules that apply to a zone,
+// including those inherited from its owner.
+//
+// The mode and configuration of filter, if set, restrict the rules returned.
+//
+// API reference: https://api.cloudflare.com/#firewall-access-rule-for-a-zone-list-access-rules
+func (api *API) ListZoneAccessRules(zoneID string, filter AccessRule) ([]AccessRule, error) {
+	return api.listAccessRules("/zones/"+zoneID, filter)
+}
+
+// CreateZoneAccessRule creates a firewall access rule for a zone.
+//
+// API reference: https://api.cloudflare.com/#firewall-access-rule-for-a-zone-create-access-rule
+func (api *API) CreateZoneAccessRule(zoneID string, rule AccessRule) (AccessRule, error) {
+	return api.createAccessRule("/zones/"+zoneID, rule)
+}
+
+// UpdateZoneAccessRule changes the mode or notes of a firewall access rule for
+// a zone. The notes are always replaced, so empty notes clear them.
+//
+// API reference: https://api.cloudflare.com/#firewall-access-rule-for-a-zone-update-access-rule
+func (api *API) UpdateZoneAccessRule(zoneID, ruleID string, rule AccessRule) (AccessRule, error) {
+	return api.updateAccessRule("/zones/"+zoneID, ruleID, rule)
+}
+
+// DeleteZoneAccessRule deletes a firewall access rule for a zone.
+//
+// API reference: https://api.cloudflare.com/#firewall-access-rule-for-a-zone-delete-access-rule
+func (api *API) DeleteZoneAccessRule(zoneID, ruleID string) error {
+	return api.deleteAccessRule("/zones/"+zoneID, ruleID)
+}
+
+// ListUserAccessRules returns the firewall access rules of the logged-in
+// user, which apply to all of their zones.
+//
+// The mode and configuration of filter, if set, restrict the rules returned.
+//
+// API reference: https://api.cloudflare.com/#user-level-firewall-access-rule-list-access-rules
+func (api *API) ListUserAccessRules(filter AccessRule) ([]AccessRule, error) {
+	return api.listAccessRules("/user", filter)
+}
+
+// CreateUserAccessRule creates a firewall access rule for the logged-in user.
+//
+// API reference: https://api.cloudflare.com/#user-level-firewall-access-rule-create-access-rule
+func (api *API) CreateUserAccessRule(rule AccessRule) (AccessRule, error) {
+	return api.createAccessRule("/user", rule)
+}
+
+// UpdateUserAccessRule changes the mode or notes of a firewall access rule
+// for the logged-in user. The notes are always replaced, so empty notes clear
+// them.
+//
+// API reference: https://api.cloudflare.com/#user-level-firewall-access-rule-update-access-rule
+func (api *API) UpdateUserAccessRule(ruleID string, rule AccessRule) (AccessRule, error) {
+	return api.updateAccessRule("/user", ruleID, rule)
+}
+
+// DeleteUserAccessRule deletes a firewall access rule for the logged-in user.
+//
+// API reference: https://api.cloudflare.com/#user-level-firewall-access-rule-delete-access-rule
+func (api *API) DeleteUserAccessRule(ruleID string) error {
+	return api.deleteAccessRule("/user", ruleID)
+}
+
+// ListOrganizationAccessRules returns the firewall access rules of an
+// organization, which apply to all of its zones.
+//
+// The mode and configuration of filter, if set, restrict the rules returned.
+//
+// API reference: https://api.cloudflare.com/#organization-level-firewall-access-rule-list-access-rules
+func (api *API) ListOrganizationAccessRules(organizationID string, filter AccessRule) ([]AccessRule, error) {
+	return api.listAccessRules("/organizations/"+organizationID, filter)
+}
+
+// CreateOrganizationAccessRule creates a firewall access rule for an
+// organization.
+//
+// API reference: https://api.cloudflare.com/#organization-level-firewall-access-rule-create-access-rule
+func (api *API) CreateOrganizationAccessRule(organizationID string, rule AccessRule) (AccessRule, error) {
+	return api.createAccessRule("/organizations/"+organizationID, rule)
+}
+
+// UpdateOrganizationAccessRule changes the mode or notes of a firewall access
+// rule for an organization. The notes are always replaced, so empty notes
+// clear them.
+//
+// API reference: https://api.cloudflare.com/#organization-level-firewall-access-rule-edit-access-rule
+func (api *API) UpdateOrganizationAccessRule(organizationID, ruleID string, rule AccessRule) (AccessRule, error) {
+	return api.updateAccessRule("/organizations/"+organizationID, ruleID, rule)
+}
+
+// DeleteOrganizationAccessRule deletes a firewall access rule for an
+// organization.
+//
+// API reference: https://api.cloudflare.com/#organization-level-firewall-access-rule-delete-access-rule
+func (api *API) DeleteOrganizationAccessRule(organizationID, ruleID string) error {
+	return api.deleteAccessRule("/organizations/"+organizationID, ruleID)
+}
+
+// listAccessRules fetches every page of the access rules under prefix, which
+// is the URI of the zone, user or organization owning them.
+func (api *API) listAccessRules(prefix string, filter AccessRule) ([]AccessRule, error) {
+	v := url.Values{}
+	v.Set("per_page", "100")
+	if filter.Mode != "" {
+		v.Set("mode", filter.Mode)
+	}
+	if filter.Configuration.Target != "" {
+		v.Set("configuration.target", filter.Configuration.Target)
+	}
+	if filter.Configuration.Value != "" {
+		v.Set("configuration.value", filter.Configuration.Value)
+	}
+	if filter.Notes != "" {
+		v.Set("notes", filter.Notes)
+	}
+
+	var rules []AccessRule
+	page := 1
+	for {
+		v.Set("page", strconv.Itoa(page))
+		uri := prefix + "/firewall/access_rules/rules?" + v.Encode()
+		res, err := api.makeRequest("GET", uri, nil)
+		if err != nil {
+			return []AccessRule{}, errors.Wrap(err, errMakeRequestError)
+		}
+		var r AccessRuleListResponse
+		err = json.Unmarshal(res, &r)
+		if err != nil {
+			return []AccessRule{}, errors.Wrap(err, errUnmarshalError)
+		}
+		if err := responseError(r.Response); err != nil {
+			return []AccessRule{}, err
+		}
+		rules = append(rules, r.Result...)
+		if r.ResultInfo.Page >= r.ResultInfo.TotalPages {
+			break
+		}
+		page++
+	}
+	return rules, nil
+}
+
+func (api *API) createAccessRule(prefix string, rule AccessRule) (AccessRule, error) {
+	if rule.Mode == "" || rule.Configuration.Target == "" || rule.Configuration.Value == "" {
+		return AccessRule{}, errors.New("access rule mode, target and value are required")
+	}
+	if err := rule.Validate(); err != nil {
+		return AccessRule{}, err
+	}
+	return api.accessRuleRequest("POST", prefix+"/firewall/access_rules/rules", rule)
+}
+
+// accessRuleUpdate is the body of an access rule update, which only includes
+// the configuration if it is being changed. Notes are always sent so that
+// they can be cleared.
+type accessRuleUpdate struct {
+	Mode          string                   `json:"mode,omitempty"`
+	Notes         string                   `json:"notes"`
+	Configuration *AccessRuleConfiguration `json:"configuration,omitempty"`
+}
+
+func (api *API) updateAccessRule(prefix, ruleID string, rule AccessRule) (AccessRule, error) {
+	if err := rule.Validate(); err != nil {
+		return AccessRule{}, err
+	}
+	params := accessRuleUpdate{Mode: rule.Mode, Notes: rule.Notes}
+	if rule.Configuration != (AccessRuleConfiguration{}) {
+		params.Configuration = &rule.Configuration
+	}
+	return api.accessRuleRequest("PATCH", prefix+"/firewall/access_rules/rules/"+ruleID, params)
+}
+
+func (api *API) deleteAccessRule(prefix, ruleID string) error {
+	_, err := api.accessRuleRequest("DELETE", prefix+"/firewall/access_rules/rules/"+ruleID, nil)
+	return err
+}
+
+// accessRuleRequest makes a request to an access rule endpoint returning a
+// single rule.
+func (api *API) accessRuleRequest(method, uri string, params interface{}) (AccessRule, error) {
+	res, err := api.makeRequest(method, uri, params)
+	if err != nil {
+		return AccessRule{}, errors.Wrap(err, errMakeRequestError)
+	}
+	var r AccessRuleResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return AccessRule{}, errors.Wrap(err, errUnmarshalError)
+	}
+	if err := responseError(r.Response); err != nil {
+		return AccessRule{}, err
+	}
+	return r.Result, nil
+}
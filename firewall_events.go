@@ -0,0 +1,299 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FirewallEvent is a request that was blocked, challenged, logged or
+// otherwise acted on by the WAF, firewall rules, rate limiting, access rules,
+// Zone Lockdown or User-Agent rules.
+type FirewallEvent struct {
+	RayID      string               `json:"ray_id"`
+	Kind       string               `json:"kind"`
+	Source     string               `json:"source"`
+	Action     string               `json:"action"`
+	RuleID     string               `json:"rule_id"`
+	IP         string               `json:"ip"`
+	IPClass    string               `json:"ip_class"`
+	Country    string               `json:"country"`
+	Colo       string               `json:"colo"`
+	Host       string               `json:"host"`
+	Method     string               `json:"method"`
+	Protocol   string               `json:"proto"`
+	Scheme     string               `json:"scheme"`
+	UserAgent  string               `json:"ua"`
+	URI        string               `json:"uri"`
+	OccurredAt time.Time            `json:"occurred_at"`
+	Matches    []FirewallEventMatch `json:"matches"`
+}
+
+// FirewallEventMatch is one of the rules matched by the request of a
+// firewall event. A request blocked by the WAF in anomaly mode, for example,
+// matches several rules.
+type FirewallEventMatch struct {
+	RuleID string `json:"rule_id"`
+	Source string `json:"source"`
+	Action string `json:"action"`
+}
+
+// FirewallEventsOptions restricts the firewall events returned. Empty fields
+// are ignored.
+type FirewallEventsOptions struct {
+	Since  time.Time
+	Until  time.Time
+	Action string
+	RuleID string
+	IP     string
+	// PerPage is the number of events fetched in each request.
+	PerPage int
+}
+
+func (o FirewallEventsOptions) encode() url.Values {
+	v := url.Values{}
+	if !o.Since.IsZero() {
+		v.Set("since", o.Since.UTC().Format(time.RFC3339))
+	}
+	if !o.Until.IsZero() {
+		v.Set("until", o.Until.UTC().Format(time.RFC3339))
+	}
+	if o.Action != "" {
+		v.Set("action", o.Action)
+	}
+	if o.RuleID != "" {
+		v.Set("rule_id", o.RuleID)
+	}
+	if o.IP != "" {
+		v.Set("ip", o.IP)
+	}
+	if o.PerPage > 0 {
+		v.Set("limit", strconv.Itoa(o.PerPage))
+	}
+	return v
+}
+
+// FirewallEventIterator iterates over firewall events, most recent first.
+// Events are decoded one at a time as they are read from each page of the
+// response, so long time ranges can be processed without holding them in
+// memory.
+//
+//	it := api.FirewallEventIterator(zoneID, opts)
+//	defer it.Close()
+//	for it.Next() {
+//		e := it.Event()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type FirewallEventIterator struct {
+	api    *API
+	zoneID string
+	query  url.Values
+
+	body  io.ReadCloser
+	dec   *json.Decoder
+	page  firewallEventsPage
+	event FirewallEvent
+	err   error
+	done  bool
+}
+
+// firewallEventsPage holds the fields of a page of firewall events other than
+// the events themselves.
+type firewallEventsPage struct {
+	Response
+	ResultInfo struct {
+		Cursors struct {
+			After string `json:"after"`
+		} `json:"cursors"`
+	} `json:"result_info"`
+}
+
+// FirewallEventIterator returns an iterator over the firewall events of a
+// zone. No request is made until Next is called.
+//
+// API reference: https://api.cloudflare.com/#firewall-events-list-events
+func (api *API) FirewallEventIterator(zoneID string, opts FirewallEventsOptions) *FirewallEventIterator {
+	return &FirewallEventIterator{api: api, zoneID: zoneID, query: opts.encode()}
+}
+
+// FirewallEvents returns all firewall events of a zone matching opts. Use
+// FirewallEventIterator to avoid holding them all in memory.
+func (api *API) FirewallEvents(zoneID string, opts FirewallEventsOptions) ([]FirewallEvent, error) {
+	it := api.FirewallEventIterator(zoneID, opts)
+	defer it.Close()
+	var events []FirewallEvent
+	for it.Next() {
+		events = append(events, it.Event())
+	}
+	return events, it.Err()
+}
+
+// Next advances to the next event, fetching the next page if needed. It
+// returns false when there are no more events or an error occurred.
+func (it *FirewallEventIterator) Next() bool {
+	for it.err == nil {
+		if it.dec == nil {
+			if it.done {
+				return false
+			}
+			it.err = it.openPage()
+			continue
+		}
+		if it.dec.More() {
+			it.event = FirewallEvent{}
+			if err := it.dec.Decode(&it.event); err != nil {
+				it.err = errors.Wrap(err, errUnmarshalError)
+				return false
+			}
+			return true
+		}
+		it.err = it.closePage()
+	}
+	it.Close()
+	return false
+}
+
+// Event returns the current event.
+func (it *FirewallEventIterator) Event() FirewallEvent {
+	return it.event
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *FirewallEventIterator) Err() error {
+	return it.err
+}
+
+// Close releases the response being read, if any. It is only needed if
+// iteration is stopped before Next returns false.
+func (it *FirewallEventIterator) Close() error {
+	it.dec = nil
+	if it.body == nil {
+		return nil
+	}
+	err := it.body.Close()
+	it.body = nil
+	return err
+}
+
+// openPage requests the next page and reads the response up to the start of
+// the result array.
+func (it *FirewallEventIterator) openPage() error {
+	uri := "/zones/" + it.zoneID + "/security/events?" + it.query.Encode()
+	resp, err := it.api.request("GET", uri, nil, it.api.authType)
+	if err != nil {
+		return errors.Wrap(err, errMakeRequestError)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		return errors.Errorf("HTTP status %d: content %q", resp.StatusCode, string(body))
+	}
+	it.body = resp.Body
+	it.dec = json.NewDecoder(resp.Body)
+	it.page = firewallEventsPage{}
+
+	if err := expectDelim(it.dec, '{'); err != nil {
+		return err
+	}
+	for it.dec.More() {
+		key, err := it.dec.Token()
+		if err != nil {
+			return errors.Wrap(err, errUnmarshalError)
+		}
+		if key != "result" {
+			if err := it.decodePageField(key); err != nil {
+				return err
+			}
+			continue
+		}
+		tok, err := it.dec.Token()
+		if err != nil {
+			return errors.Wrap(err, errUnmarshalError)
+		}
+		switch tok {
+		case json.Delim('['):
+			return nil
+		case nil:
+			// Failed requests have a null result.
+			return it.finishPage()
+		}
+		return errors.Wrap(errors.Errorf("unexpected result %v", tok), errUnmarshalError)
+	}
+	return it.finishPage()
+}
+
+// closePage reads the end of the result array and the rest of the page.
+func (it *FirewallEventIterator) closePage() error {
+	if err := expectDelim(it.dec, ']'); err != nil {
+		return err
+	}
+	return it.finishPage()
+}
+
+// finishPage reads the fields of a page after its result, and works out
+// whether there is another page.
+func (it *FirewallEventIterator) finishPage() error {
+	for it.dec.More() {
+		key, err := it.dec.Token()
+		if err != nil {
+			return errors.Wrap(err, errUnmarshalError)
+		}
+		if err := it.decodePageField(key); err != nil {
+			return err
+		}
+	}
+	it.Close()
+	if err := responseError(it.page.Response); err != nil {
+		return err
+	}
+	cursor := it.page.ResultInfo.Cursors.After
+	if cursor == "" || cursor == it.query.Get("cursor") {
+		it.done = true
+		return nil
+	}
+	it.query.Set("cursor", cursor)
+	return nil
+}
+
+// decodePageField decodes the value of key, other than result, into the
+// page.
+func (it *FirewallEventIterator) decodePageField(key json.Token) error {
+	var dst interface{}
+	switch key {
+	case "success":
+		dst = &it.page.Success
+	case "errors":
+		dst = &it.page.Errors
+	case "messages":
+		dst = &it.page.Messages
+	case "result_info":
+		dst = &it.page.ResultInfo
+	default:
+		dst = &json.RawMessage{}
+	}
+	if err := it.dec.Decode(dst); err != nil {
+		return errors.Wrap(err, errUnmarshalError)
+	}
+	return nil
+}
+
+// expectDelim reads the next token from dec and checks that it is delim.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return errors.Wrap(err, errUnmarshalError)
+	}
+	if tok != delim {
+		return errors.Wrap(errors.Errorf("expected %v but found %v", delim, tok), errUnmarshalError)
+	}
+	return nil
+}
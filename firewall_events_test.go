@@ -0,0 +1,75 @@
+package cloudflare
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFirewallEventIterator(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/security/events", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method, "Expected method 'GET', got %s", r.Method)
+		q := r.URL.Query()
+		assert.Equal(t, "2018-06-01T10:00:00Z", q.Get("since"))
+		assert.Equal(t, "block", q.Get("action"))
+		w.Header().Set("content-type", "application/json")
+		switch q.Get("cursor") {
+		case "":
+			fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": [
+				{"ray_id": "ray1", "kind": "firewall", "source": "waf", "action": "block", "rule_id": "100000",
+				 "ip": "198.51.100.4", "country": "GB", "colo": "LHR", "host": "example.com", "method": "GET",
+				 "proto": "HTTP/1.1", "scheme": "https", "ua": "curl/7.54.0", "uri": "/admin",
+				 "occurred_at": "2018-06-01T10:59:00Z", "matches": [{"rule_id": "100000", "source": "waf", "action": "block"}]},
+				{"ray_id": "ray2", "action": "block", "occurred_at": "2018-06-01T10:58:00Z"}
+			], "result_info": {"cursors": {"after": "next", "before": "prev"}}}`)
+		case "next":
+			// The result info comes first on this page.
+			fmt.Fprint(w, `{"result_info": {"cursors": {"after": ""}}, "success": true, "errors": [], "messages": [], "result": [
+				{"ray_id": "ray3", "action": "block", "occurred_at": "2018-06-01T10:01:00Z"}
+			]}`)
+		default:
+			t.Errorf("unexpected cursor %q", q.Get("cursor"))
+		}
+	})
+
+	opts := FirewallEventsOptions{
+		Since:  time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC),
+		Action: "block",
+	}
+	it := client.FirewallEventIterator("abc", opts)
+	var rays []string
+	for it.Next() {
+		rays = append(rays, it.Event().RayID)
+		if it.Event().RayID == "ray1" {
+			e := it.Event()
+			assert.Equal(t, "curl/7.54.0", e.UserAgent)
+			assert.Equal(t, "HTTP/1.1", e.Protocol)
+			assert.Len(t, e.Matches, 1)
+			assert.Equal(t, time.Date(2018, 6, 1, 10, 59, 0, 0, time.UTC), e.OccurredAt)
+		}
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"ray1", "ray2", "ray3"}, rays)
+}
+
+func TestFirewallEventsNotSuccessful(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/security/events", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": false, "errors": [{"code": 10000, "message": "time range too large"}], "messages": [], "result": null}`)
+	})
+
+	events, err := client.FirewallEvents("abc", FirewallEventsOptions{})
+	assert.Empty(t, events)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "time range too large (10000)")
+	}
+}
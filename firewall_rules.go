@@ -0,0 +1,191 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FirewallRule is a firewall rule, which takes an action on requests matched
+// by a filter.
+//
+// Action can be one of block, challenge, js_challenge, allow, log or bypass.
+// Products lists the features bypassed by the bypass action, such as waf,
+// rateLimit or uaBlock. Rules are evaluated in order of Priority, lowest
+// first; rules without a priority are evaluated last.
+//
+// When creating a rule, Filter can either refer to an existing filter by ID
+// or contain the expression of a new filter to create along with the rule.
+type FirewallRule struct {
+	ID          string     `json:"id,omitempty"`
+	Paused      bool       `json:"paused"`
+	Description string     `json:"description,omitempty"`
+	Action      string     `json:"action"`
+	Priority    int        `json:"priority,omitempty"`
+	Filter      Filter     `json:"filter"`
+	Products    []string   `json:"products,omitempty"`
+	Ref         string     `json:"ref,omitempty"`
+	CreatedOn   *time.Time `json:"created_on,omitempty"`
+	ModifiedOn  *time.Time `json:"modified_on,omitempty"`
+}
+
+// FirewallRuleResponse represents the response from the firewall rule
+// endpoint for a single rule.
+type FirewallRuleResponse struct {
+	Response
+	Result FirewallRule `json:"result"`
+}
+
+// FirewallRulesResponse represents the response from the firewall rules
+// endpoint.
+type FirewallRulesResponse struct {
+	Response
+	Result     []FirewallRule `json:"result"`
+	ResultInfo ResultInfo     `json:"result_info"`
+}
+
+// firewallRuleActions holds the actions accepted by the API.
+var firewallRuleActions = map[string]bool{
+	"block":        true,
+	"challenge":    true,
+	"js_challenge": true,
+	"allow":        true,
+	"log":          true,
+	"bypass":       true,
+}
+
+// Validate checks the action of a firewall rule and that it has a filter.
+func (r FirewallRule) Validate() error {
+	if !firewallRuleActions[r.Action] {
+		return errors.Errorf("invalid firewall rule action %q", r.Action)
+	}
+	if r.Action == "bypass" && len(r.Products) == 0 {
+		return errors.New("firewall rule with action bypass requires products")
+	}
+	if r.Action != "bypass" && len(r.Products) > 0 {
+		return errors.Errorf("firewall rule with action %s can't have products", r.Action)
+	}
+	if r.Filter.ID == "" && r.Filter.Expression == "" {
+		return errors.New("firewall rule requires a filter ID or expression")
+	}
+	return nil
+}
+
+// ListFirewallRules returns all firewall rules for a zone.
+//
+// API reference: https://api.cloudflare.com/#firewall-rules-list-of-firewall-rules
+func (api *API) ListFirewallRules(zoneID string) ([]FirewallRule, error) {
+	v := url.Values{}
+	v.Set("per_page", "100")
+
+	var rules []FirewallRule
+	page := 1
+	for {
+		v.Set("page", strconv.Itoa(page))
+		uri := "/zones/" + zoneID + "/firewall/rules?" + v.Encode()
+		res, err := api.makeRequest("GET", uri, nil)
+		if err != nil {
+			return []FirewallRule{}, errors.Wrap(err, errMakeRequestError)
+		}
+		var r FirewallRulesResponse
+		err = json.Unmarshal(res, &r)
+		if err != nil {
+			return []FirewallRule{}, errors.Wrap(err, errUnmarshalError)
+		}
+		if err := responseError(r.Response); err != nil {
+			return []FirewallRule{}, err
+		}
+		rules = append(rules, r.Result...)
+		if r.ResultInfo.Page >= r.ResultInfo.TotalPages {
+			break
+		}
+		page++
+	}
+	return rules, nil
+}
+
+// FirewallRule returns a single firewall rule.
+//
+// API reference: https://api.cloudflare.com/#firewall-rules-get-firewall-rule-by-id
+func (api *API) FirewallRule(zoneID, ruleID string) (FirewallRule, error) {
+	uri := "/zones/" + zoneID + "/firewall/rules/" + ruleID
+	res, err := api.makeRequest("GET", uri, nil)
+	if err != nil {
+		return FirewallRule{}, errors.Wrap(err, errMakeRequestError)
+	}
+	var r FirewallRuleResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return FirewallRule{}, errors.Wrap(err, errUnmarshalError)
+	}
+	if err := responseError(r.Response); err != nil {
+		return FirewallRule{}, err
+	}
+	return r.Result, nil
+}
+
+// CreateFirewallRules creates firewall rules for a zone in a single request
+// and returns them with their IDs.
+//
+// API reference: https://api.cloudflare.com/#firewall-rules-create-firewall-rules
+func (api *API) CreateFirewallRules(zoneID string, rules []FirewallRule) ([]FirewallRule, error) {
+	for _, r := range rules {
+		if err := r.Validate(); err != nil {
+			return []FirewallRule{}, err
+		}
+	}
+	uri := "/zones/" + zoneID + "/firewall/rules"
+	return api.firewallRulesRequest("POST", uri, rules)
+}
+
+// UpdateFirewallRules replaces firewall rules in a single request. Each rule
+// must have an ID.
+//
+// API reference: https://api.cloudflare.com/#firewall-rules-update-firewall-rules
+func (api *API) UpdateFirewallRules(zoneID string, rules []FirewallRule) ([]FirewallRule, error) {
+	for _, r := range rules {
+		if r.ID == "" {
+			return []FirewallRule{}, errors.New("firewall rule ID is required to update a rule")
+		}
+		if err := r.Validate(); err != nil {
+			return []FirewallRule{}, err
+		}
+	}
+	uri := "/zones/" + zoneID + "/firewall/rules"
+	return api.firewallRulesRequest("PUT", uri, rules)
+}
+
+// DeleteFirewallRules deletes firewall rules in a single request. Their
+// filters are not deleted.
+//
+// API reference: https://api.cloudflare.com/#firewall-rules-delete-firewall-rules
+func (api *API) DeleteFirewallRules(zoneID string, ruleIDs []string) error {
+	v := url.Values{}
+	for _, id := range ruleIDs {
+		v.Add("id", id)
+	}
+	uri := "/zones/" + zoneID + "/firewall/rules?" + v.Encode()
+	_, err := api.firewallRulesRequest("DELETE", uri, nil)
+	return err
+}
+
+// firewallRulesRequest makes a request to the firewall rules endpoint
+// returning a list of rules.
+func (api *API) firewallRulesRequest(method, uri string, params interface{}) ([]FirewallRule, error) {
+	res, err := api.makeRequest(method, uri, params)
+	if err != nil {
+		return []FirewallRule{}, errors.Wrap(err, errMakeRequestError)
+	}
+	var r FirewallRulesResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return []FirewallRule{}, errors.Wrap(err, errUnmarshalError)
+	}
+	if err := responseError(r.Response); err != nil {
+		return []FirewallRule{}, err
+	}
+	return r.Result, nil
+}
@@ -0,0 +1,56 @@
+package cloudflare
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateFirewallRules(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/firewall/rules", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method, "Expected method 'POST', got %s", r.Method)
+		b, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `[
+			{"paused":false,"action":"block","filter":{"id":"f1","paused":false}},
+			{"paused":false,"action":"bypass","priority":1,"filter":{"expression":"ip.src in {198.51.100.0/24}","paused":false},"products":["waf"]}
+		]`, string(b))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": [
+			{"id": "r1", "paused": false, "action": "block", "filter": {"id": "f1", "expression": "ip.src eq 198.51.100.4"}},
+			{"id": "r2", "paused": false, "action": "bypass", "priority": 1, "products": ["waf"],
+			 "filter": {"id": "f2", "expression": "ip.src in {198.51.100.0/24}"}}
+		]}`)
+	})
+
+	rules := []FirewallRule{
+		{Action: "block", Filter: Filter{ID: "f1"}},
+		{Action: "bypass", Priority: 1, Products: []string{"waf"}, Filter: Filter{Expression: "ip.src in {198.51.100.0/24}"}},
+	}
+	created, err := client.CreateFirewallRules("abc", rules)
+	if assert.NoError(t, err) && assert.Len(t, created, 2) {
+		assert.Equal(t, "r1", created[0].ID)
+		assert.Equal(t, "f2", created[1].Filter.ID)
+	}
+}
+
+func TestFirewallRuleValidate(t *testing.T) {
+	assert.NoError(t, FirewallRule{Action: "log", Filter: Filter{ID: "f1"}}.Validate())
+	assert.Error(t, FirewallRule{Action: "drop", Filter: Filter{ID: "f1"}}.Validate())
+	assert.Error(t, FirewallRule{Action: "bypass", Filter: Filter{ID: "f1"}}.Validate())
+	assert.Error(t, FirewallRule{Action: "block", Products: []string{"waf"}, Filter: Filter{ID: "f1"}}.Validate())
+	assert.Error(t, FirewallRule{Action: "block"}.Validate())
+}
+
+func TestUpdateFirewallRulesRequiresID(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.UpdateFirewallRules("abc", []FirewallRule{{Action: "block", Filter: Filter{ID: "f1"}}})
+	assert.Error(t, err)
+}
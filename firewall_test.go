@@ -0,0 +1,126 @@
+package cloudflare
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListZoneAccessRules(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/firewall/access_rules/rules", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method, "Expected method 'GET', got %s", r.Method)
+		q := r.URL.Query()
+		assert.Equal(t, "block", q.Get("mode"))
+		assert.Equal(t, "198.51.100.4", q.Get("configuration.value"))
+		page := q.Get("page")
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": [
+			{"id": "rule%s", "notes": "incident", "allowed_modes": ["whitelist", "block", "challenge"], "mode": "block",
+			 "configuration": {"target": "ip", "value": "198.51.100.4"},
+			 "scope": {"id": "abc", "name": "example.com", "type": "zone"},
+			 "created_on": "2014-01-01T05:20:00.12345Z", "modified_on": "2014-01-01T05:20:00.12345Z"}
+		], "result_info": {"page": %s, "per_page": 1, "total_pages": 2, "count": 1, "total_count": 2}}`, page, page)
+	})
+
+	filter := AccessRule{Mode: "block", Configuration: AccessRuleConfiguration{Value: "198.51.100.4"}}
+	rules, err := client.ListZoneAccessRules("abc", filter)
+	if assert.NoError(t, err) && assert.Len(t, rules, 2) {
+		assert.Equal(t, "rule1", rules[0].ID)
+		assert.Equal(t, "rule2", rules[1].ID)
+		assert.Equal(t, "ip", rules[0].Configuration.Target)
+		assert.Equal(t, "zone", rules[0].Scope.Type)
+	}
+}
+
+func TestCreateOrganizationAccessRule(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/organizations/org/firewall/access_rules/rules", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method, "Expected method 'POST', got %s", r.Method)
+		b, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `{"mode":"challenge","notes":"scanner","configuration":{"target":"asn","value":"AS64496"}}`, string(b))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result":
+			{"id": "new", "mode": "challenge", "notes": "scanner", "configuration": {"target": "asn", "value": "AS64496"}}}`)
+	})
+
+	rule := AccessRule{
+		Mode:          AccessRuleChallenge,
+		Notes:         "scanner",
+		Configuration: AccessRuleConfiguration{Target: "asn", Value: "AS64496"},
+	}
+	created, err := client.CreateOrganizationAccessRule("org", rule)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "new", created.ID)
+	}
+
+	rule.Mode = "deny"
+	_, err = client.CreateOrganizationAccessRule("org", rule)
+	assert.Error(t, err)
+}
+
+func TestUpdateAccessRules(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var body string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PATCH", r.Method)
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":
+			{"id":"rule","mode":"challenge","notes":"noisy","configuration":{"target":"ip","value":"198.51.100.4"}}}`)
+	}
+	mux.HandleFunc("/zones/abc/firewall/access_rules/rules/rule", handler)
+	mux.HandleFunc("/user/firewall/access_rules/rules/rule", handler)
+	mux.HandleFunc("/organizations/org/firewall/access_rules/rules/rule", handler)
+
+	// Only the fields being changed are sent.
+	update := AccessRule{Mode: "challenge", Notes: "noisy"}
+	rule, err := client.UpdateZoneAccessRule("abc", "rule", update)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"mode":"challenge","notes":"noisy"}`, body)
+		assert.Equal(t, "198.51.100.4", rule.Configuration.Value)
+	}
+	_, err = client.UpdateUserAccessRule("rule", update)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"mode":"challenge","notes":"noisy"}`, body)
+	}
+	_, err = client.UpdateOrganizationAccessRule("org", "rule", AccessRule{
+		Notes:         "moved",
+		Configuration: AccessRuleConfiguration{Target: "ip", Value: "198.51.100.5"},
+	})
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"notes":"moved","configuration":{"target":"ip","value":"198.51.100.5"}}`, body)
+	}
+
+	// Empty notes clear them.
+	_, err = client.UpdateZoneAccessRule("abc", "rule", AccessRule{Mode: "block"})
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"mode":"block","notes":""}`, body)
+	}
+}
+
+func TestDeleteUserAccessRule(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/user/firewall/access_rules/rules/gone", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method, "Expected method 'DELETE', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": false, "errors": [{"code": 10001, "message": "not found"}], "messages": [], "result": null}`)
+	})
+
+	err := client.DeleteUserAccessRule("gone")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "not found (10001)")
+	}
+}
@@ -0,0 +1,155 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ZoneLockdown represents a Zone Lockdown rule, which only allows requests
+// to the given URLs from the given IP addresses and ranges.
+type ZoneLockdown struct {
+	ID             string               `json:"id,omitempty"`
+	Description    string               `json:"description"`
+	URLs           []string             `json:"urls"`
+	Configurations []ZoneLockdownConfig `json:"configurations"`
+	Paused         bool                 `json:"paused"`
+	Priority       int                  `json:"priority,omitempty"`
+	CreatedOn      *time.Time           `json:"created_on,omitempty"`
+	ModifiedOn     *time.Time           `json:"modified_on,omitempty"`
+}
+
+// ZoneLockdownConfig is an IP address or range allowed by a Zone Lockdown
+// rule. Target can be ip or ip_range.
+type ZoneLockdownConfig struct {
+	Target string `json:"target"`
+	Value  string `json:"value"`
+}
+
+// ZoneLockdownResponse represents the response from the Zone Lockdown
+// endpoint for a single rule.
+type ZoneLockdownResponse struct {
+	Response
+	Result ZoneLockdown `json:"result"`
+}
+
+// ZoneLockdownListResponse represents the response from the Zone Lockdown
+// endpoint.
+type ZoneLockdownListResponse struct {
+	Response
+	Result     []ZoneLockdown `json:"result"`
+	ResultInfo ResultInfo     `json:"result_info"`
+}
+
+// Validate checks that a Zone Lockdown rule has at least one URL and one
+// IP address or range.
+func (l ZoneLockdown) Validate() error {
+	if len(l.URLs) == 0 {
+		return errors.New("zone lockdown requires at least one URL")
+	}
+	if len(l.Configurations) == 0 {
+		return errors.New("zone lockdown requires at least one IP address or range")
+	}
+	for _, c := range l.Configurations {
+		if c.Target != "ip" && c.Target != "ip_range" {
+			return errors.Errorf("invalid zone lockdown target %q: must be ip or ip_range", c.Target)
+		}
+		if c.Value == "" {
+			return errors.New("zone lockdown configuration value is empty")
+		}
+	}
+	return nil
+}
+
+// ListZoneLockdowns returns all Zone Lockdown rules for a zone.
+//
+// API reference: https://api.cloudflare.com/#zone-lockdown-list-lockdown-rules
+func (api *API) ListZoneLockdowns(zoneID string) ([]ZoneLockdown, error) {
+	v := url.Values{}
+	v.Set("per_page", "100")
+
+	var lockdowns []ZoneLockdown
+	page := 1
+	for {
+		v.Set("page", strconv.Itoa(page))
+		uri := "/zones/" + zoneID + "/firewall/lockdowns?" + v.Encode()
+		res, err := api.makeRequest("GET", uri, nil)
+		if err != nil {
+			return []ZoneLockdown{}, errors.Wrap(err, errMakeRequestError)
+		}
+		var r ZoneLockdownListResponse
+		err = json.Unmarshal(res, &r)
+		if err != nil {
+			return []ZoneLockdown{}, errors.Wrap(err, errUnmarshalError)
+		}
+		if err := responseError(r.Response); err != nil {
+			return []ZoneLockdown{}, err
+		}
+		lockdowns = append(lockdowns, r.Result...)
+		if r.ResultInfo.Page >= r.ResultInfo.TotalPages {
+			break
+		}
+		page++
+	}
+	return lockdowns, nil
+}
+
+// ZoneLockdown returns a single Zone Lockdown rule.
+//
+// API reference: https://api.cloudflare.com/#zone-lockdown-lockdown-rule-details
+func (api *API) ZoneLockdown(zoneID, id string) (ZoneLockdown, error) {
+	uri := "/zones/" + zoneID + "/firewall/lockdowns/" + id
+	return api.zoneLockdownRequest("GET", uri, nil)
+}
+
+// CreateZoneLockdown creates a Zone Lockdown rule for a zone.
+//
+// API reference: https://api.cloudflare.com/#zone-lockdown-create-lockdown-rule
+func (api *API) CreateZoneLockdown(zoneID string, ld ZoneLockdown) (ZoneLockdown, error) {
+	if err := ld.Validate(); err != nil {
+		return ZoneLockdown{}, err
+	}
+	uri := "/zones/" + zoneID + "/firewall/lockdowns"
+	return api.zoneLockdownRequest("POST", uri, ld)
+}
+
+// UpdateZoneLockdown replaces a Zone Lockdown rule.
+//
+// API reference: https://api.cloudflare.com/#zone-lockdown-update-lockdown-rule
+func (api *API) UpdateZoneLockdown(zoneID, id string, ld ZoneLockdown) (ZoneLockdown, error) {
+	if err := ld.Validate(); err != nil {
+		return ZoneLockdown{}, err
+	}
+	uri := "/zones/" + zoneID + "/firewall/lockdowns/" + id
+	return api.zoneLockdownRequest("PUT", uri, ld)
+}
+
+// DeleteZoneLockdown deletes a Zone Lockdown rule.
+//
+// API reference: https://api.cloudflare.com/#zone-lockdown-delete-lockdown-rule
+func (api *API) DeleteZoneLockdown(zoneID, id string) error {
+	uri := "/zones/" + zoneID + "/firewall/lockdowns/" + id
+	_, err := api.zoneLockdownRequest("DELETE", uri, nil)
+	return err
+}
+
+// zoneLockdownRequest makes a request to a Zone Lockdown endpoint returning a
+// single rule.
+func (api *API) zoneLockdownRequest(method, uri string, params interface{}) (ZoneLockdown, error) {
+	res, err := api.makeRequest(method, uri, params)
+	if err != nil {
+		return ZoneLockdown{}, errors.Wrap(err, errMakeRequestError)
+	}
+	var r ZoneLockdownResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return ZoneLockdown{}, errors.Wrap(err, errUnmarshalError)
+	}
+	if err := responseError(r.Response); err != nil {
+		return ZoneLockdown{}, err
+	}
+	return r.Result, nil
+}
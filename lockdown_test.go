@@ -0,0 +1,64 @@
+package cloudflare
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListZoneLockdowns(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/firewall/lockdowns", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method, "Expected method 'GET', got %s", r.Method)
+		page := r.URL.Query().Get("page")
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": [
+			{"id": "ld%s", "description": "Admin", "urls": ["example.com/admin*"],
+			 "configurations": [{"target": "ip_range", "value": "198.51.100.0/24"}], "paused": false}
+		], "result_info": {"page": %s, "per_page": 1, "total_pages": 2, "count": 1, "total_count": 2}}`, page, page)
+	})
+
+	lockdowns, err := client.ListZoneLockdowns("abc")
+	if assert.NoError(t, err) && assert.Len(t, lockdowns, 2) {
+		assert.Equal(t, "ld1", lockdowns[0].ID)
+		assert.Equal(t, "ld2", lockdowns[1].ID)
+		assert.Equal(t, []ZoneLockdownConfig{{Target: "ip_range", Value: "198.51.100.0/24"}}, lockdowns[0].Configurations)
+	}
+}
+
+func TestCreateZoneLockdown(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/firewall/lockdowns", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method, "Expected method 'POST', got %s", r.Method)
+		b, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `{"description":"Admin","urls":["example.com/admin*"],
+			"configurations":[{"target":"ip","value":"198.51.100.4"}],"paused":true}`, string(b))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result":
+			{"id": "new", "description": "Admin", "urls": ["example.com/admin*"],
+			 "configurations": [{"target": "ip", "value": "198.51.100.4"}], "paused": true}}`)
+	})
+
+	ld := ZoneLockdown{
+		Description:    "Admin",
+		URLs:           []string{"example.com/admin*"},
+		Configurations: []ZoneLockdownConfig{{Target: "ip", Value: "198.51.100.4"}},
+		Paused:         true,
+	}
+	created, err := client.CreateZoneLockdown("abc", ld)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "new", created.ID)
+		assert.True(t, created.Paused)
+	}
+
+	ld.Configurations = nil
+	_, err = client.CreateZoneLockdown("abc", ld)
+	assert.Error(t, err)
+}
@@ -0,0 +1,333 @@
+package cloudflare
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LogpullMaxSpan is the longest time range the API returns logs for in a
+// single request. LogsReceived splits longer ranges into requests of this
+// span.
+const LogpullMaxSpan = time.Hour
+
+// LogpullOptions selects the log fields and entries returned by
+// LogsReceived and LogsRayID.
+type LogpullOptions struct {
+	// Fields lists the fields to include in each entry; if empty, the API's
+	// default set of fields is returned.
+	Fields []string
+	// Sample, if between 0 and 1, returns only that fraction of entries.
+	Sample float64
+	// Count, if positive, is the maximum number of entries to return.
+	Count int
+	// Timestamps is the format of timestamps: unix, unixnano (the
+	// default) or rfc3339. LogTimestamp decodes all three.
+	Timestamps string
+}
+
+func (o LogpullOptions) encode(v url.Values) {
+	if len(o.Fields) > 0 {
+		v.Set("fields", strings.Join(o.Fields, ","))
+	}
+	if o.Sample > 0 && o.Sample < 1 {
+		v.Set("sample", strconv.FormatFloat(o.Sample, 'f', -1, 64))
+	}
+	if o.Timestamps != "" {
+		v.Set("timestamps", o.Timestamps)
+	}
+}
+
+// String returns the options in the form used by LogpushJob.LogpullOptions,
+// such as fields=RayID,ClientIP&timestamps=rfc3339. Count has no meaning for
+// Logpush and is left out.
+func (o LogpullOptions) String() string {
+	var parts []string
+	if len(o.Fields) > 0 {
+		parts = append(parts, "fields="+strings.Join(o.Fields, ","))
+	}
+	if o.Sample > 0 && o.Sample < 1 {
+		parts = append(parts, "sample="+strconv.FormatFloat(o.Sample, 'f', -1, 64))
+	}
+	if o.Timestamps != "" {
+		parts = append(parts, "timestamps="+o.Timestamps)
+	}
+	return strings.Join(parts, "&")
+}
+
+// LogTimestamp is a timestamp in a log entry, in any of the formats selected
+// by LogpullOptions.Timestamps.
+type LogTimestamp struct {
+	time.Time
+}
+
+// UnmarshalJSON decodes an RFC 3339 string, or a number of seconds or
+// nanoseconds since the Unix epoch.
+func (t *LogTimestamp) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '"' {
+		return json.Unmarshal(b, &t.Time)
+	}
+	n, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		return errors.Errorf("invalid timestamp %s", b)
+	}
+	// Nanosecond timestamps are larger than any plausible number of
+	// seconds.
+	if n > 1e12 {
+		t.Time = time.Unix(0, n).UTC()
+	} else {
+		t.Time = time.Unix(n, 0).UTC()
+	}
+	return nil
+}
+
+// LogEntry is a request log entry. Only the fields requested with
+// LogpullOptions.Fields are set; Raw holds the entry as received, including
+// any fields not in LogEntry.
+type LogEntry struct {
+	CacheCacheStatus       string       `json:"CacheCacheStatus"`
+	CacheResponseBytes     int64        `json:"CacheResponseBytes"`
+	CacheResponseStatus    int          `json:"CacheResponseStatus"`
+	ClientASN              int          `json:"ClientASN"`
+	ClientCountry          string       `json:"ClientCountry"`
+	ClientDeviceType       string       `json:"ClientDeviceType"`
+	ClientIP               string       `json:"ClientIP"`
+	ClientIPClass          string       `json:"ClientIPClass"`
+	ClientRequestBytes     int64        `json:"ClientRequestBytes"`
+	ClientRequestHost      string       `json:"ClientRequestHost"`
+	ClientRequestMethod    string       `json:"ClientRequestMethod"`
+	ClientRequestProtocol  string       `json:"ClientRequestProtocol"`
+	ClientRequestReferer   string       `json:"ClientRequestReferer"`
+	ClientRequestURI       string       `json:"ClientRequestURI"`
+	ClientRequestUserAgent string       `json:"ClientRequestUserAgent"`
+	ClientSSLProtocol      string       `json:"ClientSSLProtocol"`
+	ClientSrcPort          int          `json:"ClientSrcPort"`
+	EdgeColoID             int          `json:"EdgeColoID"`
+	EdgeEndTimestamp       LogTimestamp `json:"EdgeEndTimestamp"`
+	EdgePathingOp          string       `json:"EdgePathingOp"`
+	EdgePathingSrc         string       `json:"EdgePathingSrc"`
+	EdgePathingStatus      string       `json:"EdgePathingStatus"`
+	EdgeRateLimitAction    string       `json:"EdgeRateLimitAction"`
+	EdgeRateLimitID        int64        `json:"EdgeRateLimitID"`
+	EdgeRequestHost        string       `json:"EdgeRequestHost"`
+	EdgeResponseBytes      int64        `json:"EdgeResponseBytes"`
+	EdgeResponseStatus     int          `json:"EdgeResponseStatus"`
+	EdgeServerIP           string       `json:"EdgeServerIP"`
+	EdgeStartTimestamp     LogTimestamp `json:"EdgeStartTimestamp"`
+	OriginIP               string       `json:"OriginIP"`
+	OriginResponseBytes    int64        `json:"OriginResponseBytes"`
+	OriginResponseStatus   int          `json:"OriginResponseStatus"`
+	OriginResponseTime     int64        `json:"OriginResponseTime"`
+	RayID                  string       `json:"RayID"`
+	SecurityLevel          string       `json:"SecurityLevel"`
+	WAFAction              string       `json:"WAFAction"`
+	WAFFlags               string       `json:"WAFFlags"`
+	WAFMatchedVar          string       `json:"WAFMatchedVar"`
+	WAFProfile             string       `json:"WAFProfile"`
+	WAFRuleID              string       `json:"WAFRuleID"`
+	WAFRuleMessage         string       `json:"WAFRuleMessage"`
+	ZoneID                 int64        `json:"ZoneID"`
+
+	Raw json.RawMessage `json:"-"`
+}
+
+// LogIterator iterates over request log entries, decoding them one at a time
+// as the (usually gzipped) response is read.
+//
+//	it := api.LogsReceived(zoneID, start, end, opts)
+//	defer it.Close()
+//	for it.Next() {
+//		e := it.Entry()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type LogIterator struct {
+	api       *API
+	uri       string
+	query     url.Values
+	windows   [][2]time.Time
+	remaining int
+
+	body  io.Closer
+	dec   *json.Decoder
+	entry LogEntry
+	err   error
+}
+
+// LogsReceived returns an iterator over the logs of requests received by a
+// zone between start (inclusive) and end (exclusive). Ranges longer than
+// LogpullMaxSpan are fetched in several requests. No request is made until
+// Next is called.
+//
+// end must be at least a minute in the past, and logs are only kept for a
+// limited time.
+//
+// API reference: https://support.cloudflare.com/hc/en-us/articles/216672448-Enterprise-Log-Share-Logpull-REST-API
+func (api *API) LogsReceived(zoneID string, start, end time.Time, opts LogpullOptions) *LogIterator {
+	it := &LogIterator{
+		api:       api,
+		uri:       "/zones/" + zoneID + "/logs/received",
+		query:     url.Values{},
+		remaining: -1,
+	}
+	opts.encode(it.query)
+	if opts.Count > 0 {
+		it.remaining = opts.Count
+	}
+	for s := start; s.Before(end); s = s.Add(LogpullMaxSpan) {
+		e := s.Add(LogpullMaxSpan)
+		if e.After(end) {
+			e = end
+		}
+		it.windows = append(it.windows, [2]time.Time{s, e})
+	}
+	return it
+}
+
+// LogsRayID returns the log entry of the request with the given Ray ID.
+//
+// API reference: https://support.cloudflare.com/hc/en-us/articles/216672448-Enterprise-Log-Share-Logpull-REST-API
+func (api *API) LogsRayID(zoneID, rayID string, opts LogpullOptions) (LogEntry, error) {
+	it := &LogIterator{
+		api:       api,
+		uri:       "/zones/" + zoneID + "/logs/rayids/" + rayID,
+		query:     url.Values{},
+		windows:   [][2]time.Time{{}},
+		remaining: 1,
+	}
+	opts.encode(it.query)
+	defer it.Close()
+	if !it.Next() {
+		if it.err != nil {
+			return LogEntry{}, it.err
+		}
+		return LogEntry{}, errors.Errorf("no log entry for Ray ID %s", rayID)
+	}
+	return it.entry, nil
+}
+
+// Next advances to the next entry. It returns false when there are no more
+// entries or an error occurred.
+func (it *LogIterator) Next() bool {
+	for it.err == nil && it.remaining != 0 {
+		if it.dec == nil {
+			if len(it.windows) == 0 {
+				return false
+			}
+			it.err = it.open(it.windows[0])
+			it.windows = it.windows[1:]
+			continue
+		}
+		var raw json.RawMessage
+		err := it.dec.Decode(&raw)
+		if err == io.EOF {
+			it.Close()
+			continue
+		}
+		if err != nil {
+			it.err = errors.Wrap(err, errUnmarshalError)
+			break
+		}
+		it.entry = LogEntry{}
+		if err := json.Unmarshal(raw, &it.entry); err != nil {
+			it.err = errors.Wrap(err, errUnmarshalError)
+			break
+		}
+		it.entry.Raw = raw
+		if it.remaining > 0 {
+			it.remaining--
+		}
+		return true
+	}
+	it.Close()
+	return false
+}
+
+// Entry returns the current entry.
+func (it *LogIterator) Entry() LogEntry {
+	return it.entry
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *LogIterator) Err() error {
+	return it.err
+}
+
+// Close releases the response being read, if any. It is only needed if
+// iteration is stopped before Next returns false.
+func (it *LogIterator) Close() error {
+	it.dec = nil
+	if it.body == nil {
+		return nil
+	}
+	err := it.body.Close()
+	it.body = nil
+	return err
+}
+
+// gzipBody closes both the gzip reader and the response it reads.
+type gzipBody struct {
+	*gzip.Reader
+	body io.Closer
+}
+
+func (b gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
+
+// open requests the entries for window, which is zero for a Ray ID lookup.
+func (it *LogIterator) open(window [2]time.Time) error {
+	if !window[0].IsZero() {
+		it.query.Set("start", window[0].UTC().Format(time.RFC3339))
+		it.query.Set("end", window[1].UTC().Format(time.RFC3339))
+	}
+	if it.remaining > 0 {
+		it.query.Set("count", strconv.Itoa(it.remaining))
+	}
+	uri := it.uri
+	if len(it.query) > 0 {
+		uri += "?" + it.query.Encode()
+	}
+	resp, err := it.api.request("GET", uri, nil, it.api.authType)
+	if err != nil {
+		return errors.Wrap(err, errMakeRequestError)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		var r Response
+		if json.Unmarshal(body, &r) == nil && len(r.Errors) > 0 {
+			return responseError(r)
+		}
+		return errors.Errorf("HTTP status %d: content %q", resp.StatusCode, string(body))
+	}
+
+	// The logs are gzipped unless the transport has already decompressed
+	// them, so look for the gzip header rather than trusting the headers.
+	br := bufio.NewReader(resp.Body)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			resp.Body.Close()
+			return errors.Wrap(err, "could not decompress logs")
+		}
+		it.body = gzipBody{zr, resp.Body}
+		it.dec = json.NewDecoder(zr)
+		return nil
+	}
+	it.body = resp.Body
+	it.dec = json.NewDecoder(br)
+	return nil
+}
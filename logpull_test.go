@@ -0,0 +1,105 @@
+package cloudflare
+
+import (
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogsReceived(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var windows []string
+	mux.HandleFunc("/zones/abc/logs/received", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method, "Expected method 'GET', got %s", r.Method)
+		q := r.URL.Query()
+		assert.Equal(t, "RayID,EdgeStartTimestamp,ClientIP", q.Get("fields"))
+		windows = append(windows, q.Get("start")+"/"+q.Get("end"))
+
+		// Send gzipped NDJSON without a Content-Encoding header, as a
+		// download would be.
+		w.Header().Set("content-type", "application/octet-stream")
+		zw := gzip.NewWriter(w)
+		fmt.Fprintf(zw, "{\"RayID\":\"%s-1\",\"EdgeStartTimestamp\":1527847200000000000,\"ClientIP\":\"198.51.100.4\"}\n", q.Get("start")[11:13])
+		fmt.Fprintf(zw, "{\"RayID\":\"%s-2\",\"EdgeStartTimestamp\":1527847260000000000,\"ClientIP\":\"198.51.100.5\"}\n", q.Get("start")[11:13])
+		zw.Close()
+	})
+
+	start := time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(2*time.Hour + 30*time.Minute)
+	opts := LogpullOptions{Fields: []string{"RayID", "EdgeStartTimestamp", "ClientIP"}}
+	it := client.LogsReceived("abc", start, end, opts)
+	var rays []string
+	for it.Next() {
+		rays = append(rays, it.Entry().RayID)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"10-1", "10-2", "11-1", "11-2", "12-1", "12-2"}, rays)
+	assert.Equal(t, []string{
+		"2018-06-01T10:00:00Z/2018-06-01T11:00:00Z",
+		"2018-06-01T11:00:00Z/2018-06-01T12:00:00Z",
+		"2018-06-01T12:00:00Z/2018-06-01T12:30:00Z",
+	}, windows)
+}
+
+func TestLogsReceivedCount(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var counts []string
+	mux.HandleFunc("/zones/abc/logs/received", func(w http.ResponseWriter, r *http.Request) {
+		counts = append(counts, r.URL.Query().Get("count"))
+		fmt.Fprint(w, "{\"RayID\":\"a\",\"EdgeStartTimestamp\":\"2018-06-01T10:00:00Z\"}\n{\"RayID\":\"b\"}\n")
+	})
+
+	start := time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC)
+	it := client.LogsReceived("abc", start, start.Add(3*time.Hour), LogpullOptions{Count: 3, Timestamps: "rfc3339"})
+	var n int
+	for it.Next() {
+		if n == 0 {
+			assert.Equal(t, start, it.Entry().EdgeStartTimestamp.Time)
+			assert.JSONEq(t, `{"RayID":"a","EdgeStartTimestamp":"2018-06-01T10:00:00Z"}`, string(it.Entry().Raw))
+		}
+		n++
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []string{"3", "1"}, counts)
+}
+
+func TestLogsRayID(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/logs/rayids/41ddf1740f67442d", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "unix", r.URL.Query().Get("timestamps"))
+		fmt.Fprint(w, `{"RayID":"41ddf1740f67442d","EdgeStartTimestamp":1527847200,"EdgeResponseStatus":403,"WAFAction":"drop"}`)
+	})
+	mux.HandleFunc("/zones/abc/logs/rayids/unknown", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"success":false,"errors":[{"code":1002,"message":"no logs for this Ray ID"}],"messages":[],"result":null}`)
+	})
+
+	e, err := client.LogsRayID("abc", "41ddf1740f67442d", LogpullOptions{Timestamps: "unix"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 403, e.EdgeResponseStatus)
+		assert.Equal(t, "drop", e.WAFAction)
+		assert.Equal(t, time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC), e.EdgeStartTimestamp.Time)
+	}
+
+	_, err = client.LogsRayID("abc", "unknown", LogpullOptions{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "no logs for this Ray ID (1002)")
+	}
+}
+
+func TestLogpullOptionsString(t *testing.T) {
+	opts := LogpullOptions{Fields: []string{"RayID", "ClientIP"}, Sample: 0.1, Count: 10, Timestamps: "rfc3339"}
+	assert.Equal(t, "fields=RayID,ClientIP&sample=0.1&timestamps=rfc3339", opts.String())
+	assert.Equal(t, "", LogpullOptions{}.String())
+}
@@ -0,0 +1,329 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LogpushJob is a Logpush job, which pushes the logs of a dataset to cloud
+// storage.
+//
+// LogpullOptions selects the fields and format of the logs, as in the query
+// string of a Logpull request; LogpullOptions.String builds it. A new job
+// needs the OwnershipChallenge read from the file written to its
+// destination by LogpushOwnershipChallenge.
+type LogpushJob struct {
+	ID                 int        `json:"id,omitempty"`
+	Enabled            bool       `json:"enabled"`
+	Name               string     `json:"name,omitempty"`
+	Dataset            string     `json:"dataset,omitempty"`
+	LogpullOptions     string     `json:"logpull_options"`
+	DestinationConf    string     `json:"destination_conf"`
+	OwnershipChallenge string     `json:"ownership_challenge,omitempty"`
+	LastComplete       *time.Time `json:"last_complete,omitempty"`
+	LastError          *time.Time `json:"last_error,omitempty"`
+	ErrorMessage       string     `json:"error_message,omitempty"`
+}
+
+// LogpushJobResponse represents the response from the Logpush job endpoint
+// for a single job.
+type LogpushJobResponse struct {
+	Response
+	Result LogpushJob `json:"result"`
+}
+
+// LogpushJobsResponse represents the response from the Logpush jobs endpoint.
+type LogpushJobsResponse struct {
+	Response
+	Result []LogpushJob `json:"result"`
+}
+
+// LogpushFieldsResponse represents the response from the Logpush fields
+// endpoint, which maps each field to its description.
+type LogpushFieldsResponse struct {
+	Response
+	Result map[string]string `json:"result"`
+}
+
+// LogpushOwnershipChallenge describes where the ownership challenge for a
+// destination was written.
+type LogpushOwnershipChallenge struct {
+	Filename string `json:"filename"`
+	Valid    bool   `json:"valid"`
+	Message  string `json:"message"`
+}
+
+// LogpushOwnershipChallengeResponse represents the response from the Logpush
+// ownership endpoint.
+type LogpushOwnershipChallengeResponse struct {
+	Response
+	Result LogpushOwnershipChallenge `json:"result"`
+}
+
+// logpushValidationResponse represents the responses from the Logpush
+// validation endpoints.
+type logpushValidationResponse struct {
+	Response
+	Result struct {
+		Valid   bool   `json:"valid"`
+		Exists  bool   `json:"exists"`
+		Message string `json:"message"`
+	} `json:"result"`
+}
+
+// LogpushDatasets lists the datasets that Logpush jobs for a zone could push
+// when this package was written. It is a static snapshot, as the API has no
+// endpoint listing datasets, so it may be missing newer ones; LogpushFields
+// reports an error for a dataset that does not exist.
+var LogpushDatasets = []string{"http_requests", "spectrum_events", "firewall_events"}
+
+// logpushDestinationSchemes holds the URL schemes of the supported
+// destinations.
+var logpushDestinationSchemes = map[string]bool{
+	"s3":    true,
+	"gs":    true,
+	"azure": true,
+	"sumo":  true,
+}
+
+// ValidateLogpushDestinationConf checks that a destination such as
+// s3://bucket/path?region=us-west-2 names a supported storage service and a
+// bucket, without contacting the API.
+func ValidateLogpushDestinationConf(conf string) error {
+	u, err := url.Parse(conf)
+	if err != nil {
+		return errors.Wrap(err, "invalid Logpush destination")
+	}
+	if !logpushDestinationSchemes[u.Scheme] {
+		return errors.Errorf("invalid Logpush destination %q: unsupported scheme %q", conf, u.Scheme)
+	}
+	if u.Host == "" {
+		return errors.Errorf("invalid Logpush destination %q: no bucket", conf)
+	}
+	if u.Scheme == "s3" && u.Query().Get("region") == "" {
+		return errors.Errorf("invalid Logpush destination %q: S3 destinations need a region", conf)
+	}
+	return nil
+}
+
+// LogpushFields returns the fields available in a dataset, with their
+// descriptions.
+//
+// API reference: https://developers.cloudflare.com/logs/logpush/logpush-configuration-api/
+func (api *API) LogpushFields(zoneID, dataset string) (map[string]string, error) {
+	uri := "/zones/" + zoneID + "/logpush/datasets/" + dataset + "/fields"
+	res, err := api.makeRequest("GET", uri, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errMakeRequestError)
+	}
+	var r LogpushFieldsResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return nil, errors.Wrap(err, errUnmarshalError)
+	}
+	if err := responseError(r.Response); err != nil {
+		return nil, err
+	}
+	return r.Result, nil
+}
+
+// LogpushJobs returns all Logpush jobs for a zone.
+//
+// API reference: https://developers.cloudflare.com/logs/logpush/logpush-configuration-api/
+func (api *API) LogpushJobs(zoneID string) ([]LogpushJob, error) {
+	return api.logpushJobsRequest("/zones/" + zoneID + "/logpush/jobs")
+}
+
+// LogpushJobsForDataset returns the Logpush jobs for a dataset of a zone.
+//
+// API reference: https://developers.cloudflare.com/logs/logpush/logpush-configuration-api/
+func (api *API) LogpushJobsForDataset(zoneID, dataset string) ([]LogpushJob, error) {
+	return api.logpushJobsRequest("/zones/" + zoneID + "/logpush/datasets/" + dataset + "/jobs")
+}
+
+func (api *API) logpushJobsRequest(uri string) ([]LogpushJob, error) {
+	res, err := api.makeRequest("GET", uri, nil)
+	if err != nil {
+		return []LogpushJob{}, errors.Wrap(err, errMakeRequestError)
+	}
+	var r LogpushJobsResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return []LogpushJob{}, errors.Wrap(err, errUnmarshalError)
+	}
+	if err := responseError(r.Response); err != nil {
+		return []LogpushJob{}, err
+	}
+	return r.Result, nil
+}
+
+// LogpushJob returns a single Logpush job.
+//
+// API reference: https://developers.cloudflare.com/logs/logpush/logpush-configuration-api/
+func (api *API) LogpushJob(zoneID string, jobID int) (LogpushJob, error) {
+	uri := "/zones/" + zoneID + "/logpush/jobs/" + strconv.Itoa(jobID)
+	return api.logpushJobRequest("GET", uri, nil)
+}
+
+// CreateLogpushJob creates a Logpush job for a zone. The destination is
+// checked with ValidateLogpushDestinationConf first.
+//
+// API reference: https://developers.cloudflare.com/logs/logpush/logpush-configuration-api/
+func (api *API) CreateLogpushJob(zoneID string, job LogpushJob) (LogpushJob, error) {
+	if err := ValidateLogpushDestinationConf(job.DestinationConf); err != nil {
+		return LogpushJob{}, err
+	}
+	if job.OwnershipChallenge == "" {
+		return LogpushJob{}, errors.New("Logpush job requires an ownership challenge")
+	}
+	uri := "/zones/" + zoneID + "/logpush/jobs"
+	return api.logpushJobRequest("POST", uri, job)
+}
+
+// UpdateLogpushJob replaces a Logpush job. A new ownership challenge is
+// needed if the destination changes.
+//
+// API reference: https://developers.cloudflare.com/logs/logpush/logpush-configuration-api/
+func (api *API) UpdateLogpushJob(zoneID string, jobID int, job LogpushJob) (LogpushJob, error) {
+	if err := ValidateLogpushDestinationConf(job.DestinationConf); err != nil {
+		return LogpushJob{}, err
+	}
+	uri := "/zones/" + zoneID + "/logpush/jobs/" + strconv.Itoa(jobID)
+	return api.logpushJobRequest("PUT", uri, job)
+}
+
+// DeleteLogpushJob deletes a Logpush job.
+//
+// API reference: https://developers.cloudflare.com/logs/logpush/logpush-configuration-api/
+func (api *API) DeleteLogpushJob(zoneID string, jobID int) error {
+	uri := "/zones/" + zoneID + "/logpush/jobs/" + strconv.Itoa(jobID)
+	res, err := api.makeRequest("DELETE", uri, nil)
+	if err != nil {
+		return errors.Wrap(err, errMakeRequestError)
+	}
+	var r Response
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return errors.Wrap(err, errUnmarshalError)
+	}
+	return responseError(r)
+}
+
+// logpushJobRequest makes a request to a Logpush job endpoint returning a
+// single job.
+func (api *API) logpushJobRequest(method, uri string, params interface{}) (LogpushJob, error) {
+	res, err := api.makeRequest(method, uri, params)
+	if err != nil {
+		return LogpushJob{}, errors.Wrap(err, errMakeRequestError)
+	}
+	var r LogpushJobResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return LogpushJob{}, errors.Wrap(err, errUnmarshalError)
+	}
+	if err := responseError(r.Response); err != nil {
+		return LogpushJob{}, err
+	}
+	return r.Result, nil
+}
+
+// LogpushOwnershipChallenge writes an ownership challenge file to a
+// destination. The token it contains must be given as the
+// OwnershipChallenge of a new job, to prove ownership of the destination.
+//
+// API reference: https://developers.cloudflare.com/logs/logpush/logpush-configuration-api/
+func (api *API) LogpushOwnershipChallenge(zoneID, destinationConf string) (LogpushOwnershipChallenge, error) {
+	if err := ValidateLogpushDestinationConf(destinationConf); err != nil {
+		return LogpushOwnershipChallenge{}, err
+	}
+	params := struct {
+		DestinationConf string `json:"destination_conf"`
+	}{destinationConf}
+	uri := "/zones/" + zoneID + "/logpush/ownership"
+	res, err := api.makeRequest("POST", uri, params)
+	if err != nil {
+		return LogpushOwnershipChallenge{}, errors.Wrap(err, errMakeRequestError)
+	}
+	var r LogpushOwnershipChallengeResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return LogpushOwnershipChallenge{}, errors.Wrap(err, errUnmarshalError)
+	}
+	if err := responseError(r.Response); err != nil {
+		return LogpushOwnershipChallenge{}, err
+	}
+	return r.Result, nil
+}
+
+// ValidateLogpushOwnershipChallenge checks an ownership challenge token for
+// a destination. It returns nil if the token is valid.
+//
+// API reference: https://developers.cloudflare.com/logs/logpush/logpush-configuration-api/
+func (api *API) ValidateLogpushOwnershipChallenge(zoneID, destinationConf, challenge string) error {
+	params := struct {
+		DestinationConf    string `json:"destination_conf"`
+		OwnershipChallenge string `json:"ownership_challenge"`
+	}{destinationConf, challenge}
+	r, err := api.logpushValidationRequest("/zones/"+zoneID+"/logpush/ownership/validate", params)
+	if err != nil {
+		return err
+	}
+	if !r.Result.Valid {
+		return errors.Errorf("invalid ownership challenge for %s", destinationConf)
+	}
+	return nil
+}
+
+// LogpushDestinationExists reports whether a job already pushes logs to a
+// destination.
+//
+// API reference: https://developers.cloudflare.com/logs/logpush/logpush-configuration-api/
+func (api *API) LogpushDestinationExists(zoneID, destinationConf string) (bool, error) {
+	params := struct {
+		DestinationConf string `json:"destination_conf"`
+	}{destinationConf}
+	r, err := api.logpushValidationRequest("/zones/"+zoneID+"/logpush/validate/destination/exists", params)
+	if err != nil {
+		return false, err
+	}
+	return r.Result.Exists, nil
+}
+
+// ValidateLogpushOrigin checks the logpull options of a job, such as the
+// fields selected. It returns nil if they are valid, or an error with the
+// API's explanation.
+//
+// API reference: https://developers.cloudflare.com/logs/logpush/logpush-configuration-api/
+func (api *API) ValidateLogpushOrigin(zoneID, logpullOptions string) error {
+	params := struct {
+		LogpullOptions string `json:"logpull_options"`
+	}{logpullOptions}
+	r, err := api.logpushValidationRequest("/zones/"+zoneID+"/logpush/validate/origin", params)
+	if err != nil {
+		return err
+	}
+	if !r.Result.Valid {
+		if r.Result.Message != "" {
+			return errors.Errorf("invalid logpull options %q: %s", logpullOptions, r.Result.Message)
+		}
+		return errors.Errorf("invalid logpull options %q", logpullOptions)
+	}
+	return nil
+}
+
+func (api *API) logpushValidationRequest(uri string, params interface{}) (logpushValidationResponse, error) {
+	var r logpushValidationResponse
+	res, err := api.makeRequest("POST", uri, params)
+	if err != nil {
+		return r, errors.Wrap(err, errMakeRequestError)
+	}
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return r, errors.Wrap(err, errUnmarshalError)
+	}
+	return r, responseError(r.Response)
+}
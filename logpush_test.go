@@ -0,0 +1,150 @@
+package cloudflare
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLogpushDestinationConf(t *testing.T) {
+	assert.NoError(t, ValidateLogpushDestinationConf("s3://bucket/logs?region=us-west-2"))
+	assert.NoError(t, ValidateLogpushDestinationConf("gs://bucket/logs"))
+	assert.Error(t, ValidateLogpushDestinationConf("s3://bucket/logs"))
+	assert.Error(t, ValidateLogpushDestinationConf("ftp://bucket/logs"))
+	assert.Error(t, ValidateLogpushDestinationConf("gs:///logs"))
+}
+
+func TestLogpushJobs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/logpush/jobs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":[
+			{"id":1,"enabled":true,"name":"example.com","dataset":"http_requests",
+			 "logpull_options":"fields=RayID&timestamps=rfc3339","destination_conf":"gs://bucket/logs",
+			 "last_complete":"2018-08-01T10:00:00Z","last_error":null,"error_message":null}
+		]}`)
+	})
+
+	jobs, err := client.LogpushJobs("abc")
+	if assert.NoError(t, err) && assert.Len(t, jobs, 1) {
+		assert.Equal(t, 1, jobs[0].ID)
+		assert.True(t, jobs[0].Enabled)
+		assert.Equal(t, "gs://bucket/logs", jobs[0].DestinationConf)
+		assert.NotNil(t, jobs[0].LastComplete)
+		assert.Nil(t, jobs[0].LastError)
+	}
+}
+
+func TestCreateLogpushJob(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/logpush/jobs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		b, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `{"enabled":true,"dataset":"http_requests","logpull_options":"fields=RayID",
+			"destination_conf":"gs://bucket/logs","ownership_challenge":"token"}`, string(b))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":
+			{"id":2,"enabled":true,"dataset":"http_requests","logpull_options":"fields=RayID","destination_conf":"gs://bucket/logs"}}`)
+	})
+
+	job := LogpushJob{
+		Enabled:            true,
+		Dataset:            "http_requests",
+		LogpullOptions:     LogpullOptions{Fields: []string{"RayID"}}.String(),
+		DestinationConf:    "gs://bucket/logs",
+		OwnershipChallenge: "token",
+	}
+	created, err := client.CreateLogpushJob("abc", job)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2, created.ID)
+	}
+
+	job.OwnershipChallenge = ""
+	_, err = client.CreateLogpushJob("abc", job)
+	assert.Error(t, err)
+}
+
+func TestDeleteLogpushJob(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/logpush/jobs/2", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{}}`)
+	})
+
+	assert.NoError(t, client.DeleteLogpushJob("abc", 2))
+}
+
+func TestLogpushFields(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/logpush/datasets/http_requests/fields", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"RayID":"Ray ID of the request"}}`)
+	})
+
+	fields, err := client.LogpushFields("abc", "http_requests")
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]string{"RayID": "Ray ID of the request"}, fields)
+	}
+}
+
+func TestLogpushOwnershipChallenge(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/logpush/ownership", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `{"destination_conf":"gs://bucket/logs"}`, string(b))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":
+			{"filename":"logs/ownership-challenge-1234.txt","valid":true,"message":""}}`)
+	})
+	mux.HandleFunc("/zones/abc/logpush/ownership/validate", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		valid := string(b) == `{"destination_conf":"gs://bucket/logs","ownership_challenge":"good"}`
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success":true,"errors":[],"messages":[],"result":{"valid":%t}}`, valid)
+	})
+
+	challenge, err := client.LogpushOwnershipChallenge("abc", "gs://bucket/logs")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "logs/ownership-challenge-1234.txt", challenge.Filename)
+	}
+	assert.NoError(t, client.ValidateLogpushOwnershipChallenge("abc", "gs://bucket/logs", "good"))
+	assert.Error(t, client.ValidateLogpushOwnershipChallenge("abc", "gs://bucket/logs", "bad"))
+}
+
+func TestLogpushValidation(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/logpush/validate/destination/exists", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"exists":true}}`)
+	})
+	mux.HandleFunc("/zones/abc/logpush/validate/origin", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"valid":false,"message":"unknown field Foo"}}`)
+	})
+
+	exists, err := client.LogpushDestinationExists("abc", "gs://bucket/logs")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	err = client.ValidateLogpushOrigin("abc", "fields=Foo")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unknown field Foo")
+	}
+}
@@ -2,6 +2,7 @@ package cloudflare
 
 import (
 	"encoding/json"
+	"sort"
 	"time"
 
 	"github.com/pkg/errors"
@@ -44,6 +45,10 @@ Valid IDs are:
   smart_errors
   ssl
   waf
+
+Actions are most easily built with the constructor for each ID, such as
+CacheLevelAction or ForwardingURLAction, and their values read with the typed
+accessors such as Seconds or ForwardingURL.
 */
 type PageRuleAction struct {
 	ID    string      `json:"id"`
@@ -59,11 +64,11 @@ var PageRuleActions = map[string]string{
 	"cache_level":         "Cache Level",              // Value of type string
 	"disable_apps":        "Disable Apps",             // Value of type interface{}
 	"disable_performance": "Disable Performance",      // Value of type interface{}
-	"disable_railgun":     "Disable Railgun",          // Value of type string
+	"disable_railgun":     "Disable Railgun",          // Value of type interface{}
 	"disable_security":    "Disable Security",         // Value of type interface{}
 	"edge_cache_ttl":      "Edge Cache TTL",           // Value of type int
 	"email_obfuscation":   "Email Obfuscation",        // Value of type string
-	"forwarding_url":      "Forwarding URL",           // Value of type map[string]interface
+	"forwarding_url":      "Forwarding URL",           // Value of type ForwardingURL
 	"ip_geolocation":      "IP Geolocation Header",    // Value of type string
 	"mirage":              "Mirage",                   // Value of type string
 	"rocket_loader":       "Rocker Loader",            // Value of type string
@@ -77,45 +82,54 @@ var PageRuleActions = map[string]string{
 // PageRule describes a Page Rule.
 type PageRule struct {
 	ID         string           `json:"id,omitempty"`
-	Targets    []PageRuleTarget `json:"targets"`
-	Actions    []PageRuleAction `json:"actions"`
-	Priority   int              `json:"priority"`
-	Status     string           `json:"status"` // can be: active, paused
+	Targets    []PageRuleTarget `json:"targets,omitempty"`
+	Actions    []PageRuleAction `json:"actions,omitempty"`
+	Priority   int              `json:"priority,omitempty"`
+	Status     string           `json:"status,omitempty"` // can be: active, paused
 	ModifiedOn time.Time        `json:"modified_on,omitempty"`
 	CreatedOn  time.Time        `json:"created_on,omitempty"`
 }
 
 // PageRuleDetailResponse is the API response, containing a single PageRule.
 type PageRuleDetailResponse struct {
-	Success  bool     `json:"success"`
-	Errors   []string `json:"errors"`
-	Messages []string `json:"messages"`
-	Result   PageRule `json:"result"`
+	Response
+	Result PageRule `json:"result"`
 }
 
 // PageRulesResponse is the API response, containing an array of PageRules.
 type PageRulesResponse struct {
-	Success  bool       `json:"success"`
-	Errors   []string   `json:"errors"`
-	Messages []string   `json:"messages"`
-	Result   []PageRule `json:"result"`
+	Response
+	Result []PageRule `json:"result"`
 }
 
-// CreatePageRule creates a new Page Rule for a zone.
-//
-// API reference: https://api.cloudflare.com/#page-rules-for-a-zone-create-a-page-rule
-func (api *API) CreatePageRule(zoneID string, rule PageRule) error {
-	uri := "/zones/" + zoneID + "/pagerules"
-	res, err := api.makeRequest("POST", uri, rule)
+// pageRuleRequest makes a request to a Page Rule endpoint returning a single
+// Page Rule.
+func (api *API) pageRuleRequest(method, uri string, params interface{}) (PageRule, error) {
+	res, err := api.makeRequest(method, uri, params)
 	if err != nil {
-		return errors.Wrap(err, errMakeRequestError)
+		return PageRule{}, errors.Wrap(err, errMakeRequestError)
 	}
 	var r PageRuleDetailResponse
 	err = json.Unmarshal(res, &r)
 	if err != nil {
-		return errors.Wrap(err, errUnmarshalError)
+		return PageRule{}, errors.Wrap(err, errUnmarshalError)
 	}
-	return nil
+	if err := responseError(r.Response); err != nil {
+		return PageRule{}, err
+	}
+	return r.Result, nil
+}
+
+// CreatePageRule creates a new Page Rule for a zone and returns it, including
+// its ID, priority and timestamps.
+//
+// API reference: https://api.cloudflare.com/#page-rules-for-a-zone-create-a-page-rule
+func (api *API) CreatePageRule(zoneID string, rule PageRule) (PageRule, error) {
+	if err := rule.Validate(); err != nil {
+		return PageRule{}, err
+	}
+	uri := "/zones/" + zoneID + "/pagerules"
+	return api.pageRuleRequest("POST", uri, rule)
 }
 
 // ListPageRules returns all Page Rules for a zone.
@@ -132,6 +146,9 @@ func (api *API) ListPageRules(zoneID string) ([]PageRule, error) {
 	if err != nil {
 		return []PageRule{}, errors.Wrap(err, errUnmarshalError)
 	}
+	if err := responseError(r.Response); err != nil {
+		return []PageRule{}, err
+	}
 	return r.Result, nil
 }
 
@@ -140,52 +157,30 @@ func (api *API) ListPageRules(zoneID string) ([]PageRule, error) {
 // API reference: https://api.cloudflare.com/#page-rules-for-a-zone-page-rule-details
 func (api *API) PageRule(zoneID, ruleID string) (PageRule, error) {
 	uri := "/zones/" + zoneID + "/pagerules/" + ruleID
-	res, err := api.makeRequest("GET", uri, nil)
-	if err != nil {
-		return PageRule{}, errors.Wrap(err, errMakeRequestError)
-	}
-	var r PageRuleDetailResponse
-	err = json.Unmarshal(res, &r)
-	if err != nil {
-		return PageRule{}, errors.Wrap(err, errUnmarshalError)
-	}
-	return r.Result, nil
+	return api.pageRuleRequest("GET", uri, nil)
 }
 
 // ChangePageRule lets you change individual settings for a Page Rule. This is
-// in contrast to UpdatePageRule which replaces the entire Page Rule.
+// in contrast to UpdatePageRule which replaces the entire Page Rule. Fields
+// left empty in rule are not changed. The resulting Page Rule is returned.
 //
 // API reference: https://api.cloudflare.com/#page-rules-for-a-zone-change-a-page-rule
-func (api *API) ChangePageRule(zoneID, ruleID string, rule PageRule) error {
+func (api *API) ChangePageRule(zoneID, ruleID string, rule PageRule) (PageRule, error) {
 	uri := "/zones/" + zoneID + "/pagerules/" + ruleID
-	res, err := api.makeRequest("PATCH", uri, rule)
-	if err != nil {
-		return errors.Wrap(err, errMakeRequestError)
-	}
-	var r PageRuleDetailResponse
-	err = json.Unmarshal(res, &r)
-	if err != nil {
-		return errors.Wrap(err, errUnmarshalError)
-	}
-	return nil
+	return api.pageRuleRequest("PATCH", uri, rule)
 }
 
 // UpdatePageRule lets you replace a Page Rule. This is in contrast to
-// ChangePageRule which lets you change individual settings.
+// ChangePageRule which lets you change individual settings. The resulting
+// Page Rule is returned.
 //
 // API reference: https://api.cloudflare.com/#page-rules-for-a-zone-update-a-page-rule
-func (api *API) UpdatePageRule(zoneID, ruleID string, rule PageRule) error {
-	uri := "/zones/" + zoneID + "/pagerules/" + ruleID
-	res, err := api.makeRequest("PUT", uri, nil)
-	if err != nil {
-		return errors.Wrap(err, errMakeRequestError)
+func (api *API) UpdatePageRule(zoneID, ruleID string, rule PageRule) (PageRule, error) {
+	if err := rule.Validate(); err != nil {
+		return PageRule{}, err
 	}
-	var r PageRuleDetailResponse
-	err = json.Unmarshal(res, &r)
-	if err != nil {
-		return errors.Wrap(err, errUnmarshalError)
-	}
-	return nil
+	uri := "/zones/" + zoneID + "/pagerules/" + ruleID
+	return api.pageRuleRequest("PUT", uri, rule)
 }
 
 // DeletePageRule deletes a Page Rule for a zone.
@@ -202,5 +197,44 @@ func (api *API) DeletePageRule(zoneID, ruleID string) error {
 	if err != nil {
 		return errors.Wrap(err, errUnmarshalError)
 	}
-	return nil
+	return responseError(r.Response)
+}
+
+// PageRulePriority sets the priority of a single Page Rule.
+type PageRulePriority struct {
+	ID       string `json:"id"`
+	Priority int    `json:"priority"`
+}
+
+// ReorderPageRules sets the priorities of all Page Rules in a zone in a single
+// request. ruleIDs lists every rule, most important first; the first rule is
+// given the highest priority so that it takes precedence over the others.
+//
+// The rules are returned in their new order.
+func (api *API) ReorderPageRules(zoneID string, ruleIDs []string) ([]PageRule, error) {
+	priorities := make([]PageRulePriority, len(ruleIDs))
+	for i, id := range ruleIDs {
+		priorities[i] = PageRulePriority{ID: id, Priority: len(ruleIDs) - i}
+	}
+	params := struct {
+		Priorities []PageRulePriority `json:"priorities"`
+	}{priorities}
+
+	uri := "/zones/" + zoneID + "/pagerules/priorities"
+	res, err := api.makeRequest("PATCH", uri, params)
+	if err != nil {
+		return []PageRule{}, errors.Wrap(err, errMakeRequestError)
+	}
+	var r PageRulesResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return []PageRule{}, errors.Wrap(err, errUnmarshalError)
+	}
+	if err := responseError(r.Response); err != nil {
+		return []PageRule{}, err
+	}
+	sort.SliceStable(r.Result, func(i, j int) bool {
+		return r.Result[i].Priority > r.Result[j].Priority
+	})
+	return r.Result, nil
 }
@@ -0,0 +1,476 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ForwardingURL is the value of a forwarding_url Page Rule action.
+type ForwardingURL struct {
+	StatusCode int    `json:"status_code"`
+	URL        string `json:"url"`
+}
+
+// CacheLevel is the value of a cache_level Page Rule action.
+type CacheLevel string
+
+// Cache levels for the cache_level Page Rule action.
+const (
+	CacheLevelBypass          CacheLevel = "bypass"
+	CacheLevelBasic           CacheLevel = "basic"
+	CacheLevelSimplified      CacheLevel = "simplified"
+	CacheLevelAggressive      CacheLevel = "aggressive"
+	CacheLevelCacheEverything CacheLevel = "cache_everything"
+)
+
+// SSLMode is the value of an ssl Page Rule action.
+type SSLMode string
+
+// SSL modes for the ssl Page Rule action.
+const (
+	SSLModeOff      SSLMode = "off"
+	SSLModeFlexible SSLMode = "flexible"
+	SSLModeFull     SSLMode = "full"
+	SSLModeStrict   SSLMode = "strict"
+)
+
+// SecurityLevel is the value of a security_level Page Rule action.
+type SecurityLevel string
+
+// Security levels for the security_level Page Rule action.
+const (
+	SecurityLevelEssentiallyOff SecurityLevel = "essentially_off"
+	SecurityLevelLow            SecurityLevel = "low"
+	SecurityLevelMedium         SecurityLevel = "medium"
+	SecurityLevelHigh           SecurityLevel = "high"
+	SecurityLevelUnderAttack    SecurityLevel = "under_attack"
+)
+
+// RocketLoaderMode is the value of a rocket_loader Page Rule action.
+type RocketLoaderMode string
+
+// Rocket Loader modes for the rocket_loader Page Rule action.
+const (
+	RocketLoaderOn        RocketLoaderMode = "on"
+	RocketLoaderOff       RocketLoaderMode = "off"
+	RocketLoaderManual    RocketLoaderMode = "manual"
+	RocketLoaderAutomatic RocketLoaderMode = "automatic"
+)
+
+// pageRuleValueKind describes the type of value a Page Rule action takes.
+type pageRuleValueKind int
+
+const (
+	pageRuleValueNone pageRuleValueKind = iota
+	pageRuleValueOnOff
+	pageRuleValueSeconds
+	pageRuleValueString
+	pageRuleValueForwardingURL
+)
+
+// pageRuleActionKinds maps each action ID to the type of its value.
+var pageRuleActionKinds = map[string]pageRuleValueKind{
+	"always_online":       pageRuleValueOnOff,
+	"always_use_https":    pageRuleValueNone,
+	"browser_cache_ttl":   pageRuleValueSeconds,
+	"browser_check":       pageRuleValueOnOff,
+	"cache_level":         pageRuleValueString,
+	"disable_apps":        pageRuleValueNone,
+	"disable_performance": pageRuleValueNone,
+	"disable_railgun":     pageRuleValueNone,
+	"disable_security":    pageRuleValueNone,
+	"edge_cache_ttl":      pageRuleValueSeconds,
+	"email_obfuscation":   pageRuleValueOnOff,
+	"forwarding_url":      pageRuleValueForwardingURL,
+	"ip_geolocation":      pageRuleValueOnOff,
+	"mirage":              pageRuleValueOnOff,
+	"rocket_loader":       pageRuleValueString,
+	"security_level":      pageRuleValueString,
+	"server_side_exclude": pageRuleValueOnOff,
+	"smart_errors":        pageRuleValueOnOff,
+	"ssl":                 pageRuleValueString,
+	"waf":                 pageRuleValueOnOff,
+}
+
+// pageRuleActionValues lists the accepted values of the string actions.
+var pageRuleActionValues = map[string][]string{
+	"cache_level": {
+		string(CacheLevelBypass), string(CacheLevelBasic), string(CacheLevelSimplified),
+		string(CacheLevelAggressive), string(CacheLevelCacheEverything),
+	},
+	"rocket_loader": {"on", "off", "manual", "automatic"},
+	"security_level": {
+		string(SecurityLevelEssentiallyOff), string(SecurityLevelLow), string(SecurityLevelMedium),
+		string(SecurityLevelHigh), string(SecurityLevelUnderAttack),
+	},
+	"ssl": {string(SSLModeOff), string(SSLModeFlexible), string(SSLModeFull), string(SSLModeStrict)},
+}
+
+// pageRuleExclusiveActions may not be combined with any other action.
+var pageRuleExclusiveActions = []string{"forwarding_url", "always_use_https"}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+// AlwaysOnlineAction returns an always_online Page Rule action.
+func AlwaysOnlineAction(on bool) PageRuleAction {
+	return PageRuleAction{ID: "always_online", Value: onOff(on)}
+}
+
+// AlwaysUseHTTPSAction returns an always_use_https Page Rule action. It cannot
+// be combined with other actions.
+func AlwaysUseHTTPSAction() PageRuleAction {
+	return PageRuleAction{ID: "always_use_https"}
+}
+
+// BrowserCacheTTLAction returns a browser_cache_ttl Page Rule action.
+func BrowserCacheTTLAction(seconds int) PageRuleAction {
+	return PageRuleAction{ID: "browser_cache_ttl", Value: seconds}
+}
+
+// BrowserCheckAction returns a browser_check Page Rule action.
+func BrowserCheckAction(on bool) PageRuleAction {
+	return PageRuleAction{ID: "browser_check", Value: onOff(on)}
+}
+
+// CacheLevelAction returns a cache_level Page Rule action.
+func CacheLevelAction(level CacheLevel) PageRuleAction {
+	return PageRuleAction{ID: "cache_level", Value: string(level)}
+}
+
+// DisableAppsAction returns a disable_apps Page Rule action.
+func DisableAppsAction() PageRuleAction {
+	return PageRuleAction{ID: "disable_apps"}
+}
+
+// DisablePerformanceAction returns a disable_performance Page Rule action.
+func DisablePerformanceAction() PageRuleAction {
+	return PageRuleAction{ID: "disable_performance"}
+}
+
+// DisableRailgunAction returns a disable_railgun Page Rule action.
+func DisableRailgunAction() PageRuleAction {
+	return PageRuleAction{ID: "disable_railgun"}
+}
+
+// DisableSecurityAction returns a disable_security Page Rule action.
+func DisableSecurityAction() PageRuleAction {
+	return PageRuleAction{ID: "disable_security"}
+}
+
+// EdgeCacheTTLAction returns an edge_cache_ttl Page Rule action.
+func EdgeCacheTTLAction(seconds int) PageRuleAction {
+	return PageRuleAction{ID: "edge_cache_ttl", Value: seconds}
+}
+
+// EmailObfuscationAction returns an email_obfuscation Page Rule action.
+func EmailObfuscationAction(on bool) PageRuleAction {
+	return PageRuleAction{ID: "email_obfuscation", Value: onOff(on)}
+}
+
+// ForwardingURLAction returns a forwarding_url Page Rule action. It cannot be
+// combined with other actions.
+func ForwardingURLAction(statusCode int, url string) PageRuleAction {
+	return PageRuleAction{ID: "forwarding_url", Value: ForwardingURL{StatusCode: statusCode, URL: url}}
+}
+
+// IPGeolocationAction returns an ip_geolocation Page Rule action.
+func IPGeolocationAction(on bool) PageRuleAction {
+	return PageRuleAction{ID: "ip_geolocation", Value: onOff(on)}
+}
+
+// MirageAction returns a mirage Page Rule action.
+func MirageAction(on bool) PageRuleAction {
+	return PageRuleAction{ID: "mirage", Value: onOff(on)}
+}
+
+// RocketLoaderAction returns a rocket_loader Page Rule action.
+func RocketLoaderAction(mode RocketLoaderMode) PageRuleAction {
+	return PageRuleAction{ID: "rocket_loader", Value: string(mode)}
+}
+
+// SecurityLevelAction returns a security_level Page Rule action.
+func SecurityLevelAction(level SecurityLevel) PageRuleAction {
+	return PageRuleAction{ID: "security_level", Value: string(level)}
+}
+
+// ServerSideExcludeAction returns a server_side_exclude Page Rule action.
+func ServerSideExcludeAction(on bool) PageRuleAction {
+	return PageRuleAction{ID: "server_side_exclude", Value: onOff(on)}
+}
+
+// SmartErrorsAction returns a smart_errors Page Rule action.
+func SmartErrorsAction(on bool) PageRuleAction {
+	return PageRuleAction{ID: "smart_errors", Value: onOff(on)}
+}
+
+// SSLAction returns an ssl Page Rule action.
+func SSLAction(mode SSLMode) PageRuleAction {
+	return PageRuleAction{ID: "ssl", Value: string(mode)}
+}
+
+// WAFAction returns a waf Page Rule action.
+func WAFAction(on bool) PageRuleAction {
+	return PageRuleAction{ID: "waf", Value: onOff(on)}
+}
+
+// UnmarshalJSON decodes a Page Rule action, giving Value a type that depends
+// on the action ID: nil for actions without a value, string for on/off and
+// other string settings, int for TTLs and ForwardingURL for forwarding_url.
+// Unknown actions are decoded as by encoding/json.
+func (a *PageRuleAction) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID    string          `json:"id"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	a.ID = raw.ID
+	a.Value = nil
+	if len(raw.Value) == 0 || string(raw.Value) == "null" {
+		return nil
+	}
+
+	kind, ok := pageRuleActionKinds[raw.ID]
+	if !ok {
+		return json.Unmarshal(raw.Value, &a.Value)
+	}
+	var err error
+	switch kind {
+	case pageRuleValueOnOff, pageRuleValueString:
+		var s string
+		err = json.Unmarshal(raw.Value, &s)
+		a.Value = s
+	case pageRuleValueSeconds:
+		var n int
+		err = json.Unmarshal(raw.Value, &n)
+		a.Value = n
+	case pageRuleValueForwardingURL:
+		var f ForwardingURL
+		err = json.Unmarshal(raw.Value, &f)
+		a.Value = f
+	default:
+		err = json.Unmarshal(raw.Value, &a.Value)
+	}
+	return errors.Wrapf(err, "invalid value for Page Rule action %q", raw.ID)
+}
+
+// On returns the value of an on/off action such as always_online or waf.
+func (a PageRuleAction) On() (bool, error) {
+	if pageRuleActionKinds[a.ID] != pageRuleValueOnOff {
+		return false, errors.Errorf("Page Rule action %q is not an on/off setting", a.ID)
+	}
+	switch a.Value {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	}
+	return false, errors.Errorf("invalid value %v for Page Rule action %q: must be on or off", a.Value, a.ID)
+}
+
+// Seconds returns the value of browser_cache_ttl and edge_cache_ttl actions.
+func (a PageRuleAction) Seconds() (int, error) {
+	if pageRuleActionKinds[a.ID] != pageRuleValueSeconds {
+		return 0, errors.Errorf("Page Rule action %q is not a TTL", a.ID)
+	}
+	switch v := a.Value.(type) {
+	case int:
+		return v, nil
+	case float64:
+		// Values decoded into an interface{} by encoding/json.
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	}
+	return 0, errors.Errorf("invalid value %v for Page Rule action %q: must be a whole number of seconds", a.Value, a.ID)
+}
+
+// ForwardingURL returns the value of a forwarding_url action.
+func (a PageRuleAction) ForwardingURL() (ForwardingURL, error) {
+	if a.ID != "forwarding_url" {
+		return ForwardingURL{}, errors.Errorf("Page Rule action %q is not forwarding_url", a.ID)
+	}
+	switch v := a.Value.(type) {
+	case ForwardingURL:
+		return v, nil
+	case map[string]interface{}:
+		code, _ := v["status_code"].(float64)
+		u, _ := v["url"].(string)
+		return ForwardingURL{StatusCode: int(code), URL: u}, nil
+	}
+	return ForwardingURL{}, errors.Errorf("invalid value %v for Page Rule action %q", a.Value, a.ID)
+}
+
+// CacheLevel returns the value of a cache_level action.
+func (a PageRuleAction) CacheLevel() (CacheLevel, error) {
+	s, err := a.stringValue("cache_level")
+	return CacheLevel(s), err
+}
+
+// SSLMode returns the value of an ssl action.
+func (a PageRuleAction) SSLMode() (SSLMode, error) {
+	s, err := a.stringValue("ssl")
+	return SSLMode(s), err
+}
+
+// RocketLoaderMode returns the value of a rocket_loader action.
+func (a PageRuleAction) RocketLoaderMode() (RocketLoaderMode, error) {
+	s, err := a.stringValue("rocket_loader")
+	return RocketLoaderMode(s), err
+}
+
+// SecurityLevel returns the value of a security_level action.
+func (a PageRuleAction) SecurityLevel() (SecurityLevel, error) {
+	s, err := a.stringValue("security_level")
+	return SecurityLevel(s), err
+}
+
+// stringValue returns the value of a string action with the given ID,
+// checking it against the accepted values.
+func (a PageRuleAction) stringValue(id string) (string, error) {
+	if a.ID != id {
+		return "", errors.Errorf("Page Rule action %q is not %s", a.ID, id)
+	}
+	s, ok := a.Value.(string)
+	if ok {
+		for _, v := range pageRuleActionValues[id] {
+			if s == v {
+				return s, nil
+			}
+		}
+	}
+	return "", errors.Errorf("invalid value %v for Page Rule action %q", a.Value, a.ID)
+}
+
+// Validate checks the action ID and that the value has the right type and an
+// accepted value for it.
+func (a PageRuleAction) Validate() error {
+	kind, ok := pageRuleActionKinds[a.ID]
+	if !ok {
+		return errors.Errorf("unknown Page Rule action %q", a.ID)
+	}
+	switch kind {
+	case pageRuleValueNone:
+		if a.Value != nil {
+			return errors.Errorf("Page Rule action %q does not take a value", a.ID)
+		}
+	case pageRuleValueOnOff:
+		_, err := a.On()
+		return err
+	case pageRuleValueSeconds:
+		n, err := a.Seconds()
+		if err != nil {
+			return err
+		}
+		if n < 0 {
+			return errors.Errorf("invalid value %d for Page Rule action %q: must not be negative", n, a.ID)
+		}
+	case pageRuleValueString:
+		_, err := a.stringValue(a.ID)
+		return err
+	case pageRuleValueForwardingURL:
+		f, err := a.ForwardingURL()
+		if err != nil {
+			return err
+		}
+		if f.StatusCode != 301 && f.StatusCode != 302 {
+			return errors.Errorf("invalid forwarding_url status code %d: must be 301 or 302", f.StatusCode)
+		}
+		if f.URL == "" {
+			return errors.New("forwarding_url must have a URL")
+		}
+	}
+	return nil
+}
+
+// Validate checks a Page Rule before it is sent to the API: the targets, each
+// action and the combination of actions.
+func (p PageRule) Validate() error {
+	if len(p.Targets) == 0 {
+		return errors.New("Page Rule must have a target")
+	}
+	for _, t := range p.Targets {
+		if t.Target != "url" || t.Constraint.Operator != "matches" {
+			return errors.Errorf("invalid Page Rule target %q %q: must be url matches", t.Target, t.Constraint.Operator)
+		}
+		if t.Constraint.Value == "" {
+			return errors.New("Page Rule target must have a URL pattern")
+		}
+	}
+	if len(p.Actions) == 0 {
+		return errors.New("Page Rule must have at least one action")
+	}
+	seen := make(map[string]bool)
+	for _, a := range p.Actions {
+		if err := a.Validate(); err != nil {
+			return err
+		}
+		if seen[a.ID] {
+			return errors.Errorf("Page Rule action %q given more than once", a.ID)
+		}
+		seen[a.ID] = true
+	}
+	if len(p.Actions) > 1 {
+		for _, id := range pageRuleExclusiveActions {
+			if seen[id] {
+				return errors.Errorf("Page Rule action %q cannot be combined with other actions", id)
+			}
+		}
+	}
+	switch p.Status {
+	case "", "active", "paused":
+	default:
+		return errors.Errorf("invalid Page Rule status %q: must be active or paused", p.Status)
+	}
+	return nil
+}
+
+// ParsePageRuleAction builds a Page Rule action from its ID and a textual
+// value, as given on a command line: "on" or "off", a number of seconds, a
+// setting such as "aggressive", or for forwarding_url a status code and URL
+// separated by a space or comma (e.g. "301 https://example.com/$1"). Actions
+// that take no value must be given an empty one.
+func ParsePageRuleAction(id, value string) (PageRuleAction, error) {
+	kind, ok := pageRuleActionKinds[id]
+	if !ok {
+		return PageRuleAction{}, errors.Errorf("unknown Page Rule action %q", id)
+	}
+	a := PageRuleAction{ID: id}
+	switch kind {
+	case pageRuleValueNone:
+		if value != "" {
+			return PageRuleAction{}, errors.Errorf("Page Rule action %q does not take a value", id)
+		}
+	case pageRuleValueOnOff, pageRuleValueString:
+		a.Value = value
+	case pageRuleValueSeconds:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return PageRuleAction{}, errors.Errorf("invalid value %q for Page Rule action %q: must be a whole number of seconds", value, id)
+		}
+		a.Value = n
+	case pageRuleValueForwardingURL:
+		i := strings.IndexAny(value, " ,")
+		if i < 0 {
+			return PageRuleAction{}, errors.Errorf("invalid value %q for Page Rule action %q: must be a status code and URL", value, id)
+		}
+		code, err := strconv.Atoi(value[:i])
+		if err != nil {
+			return PageRuleAction{}, errors.Errorf("invalid status code %q for Page Rule action %q", value[:i], id)
+		}
+		a.Value = ForwardingURL{StatusCode: code, URL: strings.TrimSpace(value[i+1:])}
+	}
+	if err := a.Validate(); err != nil {
+		return PageRuleAction{}, err
+	}
+	return a, nil
+}
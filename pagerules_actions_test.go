@@ -0,0 +1,141 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageRuleActionJSON(t *testing.T) {
+	input := `[
+		{"id":"forwarding_url","value":{"status_code":301,"url":"https://example.com/$1"}},
+		{"id":"edge_cache_ttl","value":7200},
+		{"id":"cache_level","value":"cache_everything"},
+		{"id":"always_online","value":"on"},
+		{"id":"disable_apps"}
+	]`
+	var actions []PageRuleAction
+	if !assert.NoError(t, json.Unmarshal([]byte(input), &actions)) {
+		return
+	}
+	want := []PageRuleAction{
+		ForwardingURLAction(301, "https://example.com/$1"),
+		EdgeCacheTTLAction(7200),
+		CacheLevelAction(CacheLevelCacheEverything),
+		AlwaysOnlineAction(true),
+		DisableAppsAction(),
+	}
+	assert.Equal(t, want, actions)
+
+	b, err := json.Marshal(actions)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `[
+			{"id":"forwarding_url","value":{"status_code":301,"url":"https://example.com/$1"}},
+			{"id":"edge_cache_ttl","value":7200},
+			{"id":"cache_level","value":"cache_everything"},
+			{"id":"always_online","value":"on"},
+			{"id":"disable_apps","value":null}
+		]`, string(b))
+	}
+
+	var bad PageRuleAction
+	assert.Error(t, json.Unmarshal([]byte(`{"id":"edge_cache_ttl","value":"soon"}`), &bad))
+}
+
+func TestPageRuleActionAccessors(t *testing.T) {
+	f, err := ForwardingURLAction(302, "https://example.com").ForwardingURL()
+	assert.NoError(t, err)
+	assert.Equal(t, ForwardingURL{StatusCode: 302, URL: "https://example.com"}, f)
+
+	// Values decoded generically, as before UnmarshalJSON existed.
+	f, err = PageRuleAction{ID: "forwarding_url", Value: map[string]interface{}{"status_code": 301.0, "url": "https://example.com"}}.ForwardingURL()
+	assert.NoError(t, err)
+	assert.Equal(t, 301, f.StatusCode)
+
+	n, err := PageRuleAction{ID: "browser_cache_ttl", Value: 14400.0}.Seconds()
+	assert.NoError(t, err)
+	assert.Equal(t, 14400, n)
+
+	on, err := WAFAction(false).On()
+	assert.NoError(t, err)
+	assert.False(t, on)
+
+	mode, err := SSLAction(SSLModeStrict).SSLMode()
+	assert.NoError(t, err)
+	assert.Equal(t, SSLModeStrict, mode)
+
+	_, err = SSLAction("bogus").SSLMode()
+	assert.Error(t, err)
+
+	rl, err := RocketLoaderAction(RocketLoaderAutomatic).RocketLoaderMode()
+	assert.NoError(t, err)
+	assert.Equal(t, RocketLoaderAutomatic, rl)
+	assert.NoError(t, RocketLoaderAction(RocketLoaderManual).Validate())
+	_, err = CacheLevelAction(CacheLevelBasic).SSLMode()
+	assert.Error(t, err)
+}
+
+func TestPageRuleValidate(t *testing.T) {
+	target := PageRuleTarget{Target: "url"}
+	target.Constraint.Operator = "matches"
+	target.Constraint.Value = "example.com/*"
+
+	tests := []struct {
+		name    string
+		actions []PageRuleAction
+		wantErr bool
+	}{
+		{"single forward", []PageRuleAction{ForwardingURLAction(301, "https://example.org/$1")}, false},
+		{"several settings", []PageRuleAction{CacheLevelAction(CacheLevelAggressive), EdgeCacheTTLAction(3600), SSLAction(SSLModeFull)}, false},
+		{"forward combined", []PageRuleAction{ForwardingURLAction(301, "https://example.org"), CacheLevelAction(CacheLevelBypass)}, true},
+		{"https combined", []PageRuleAction{AlwaysUseHTTPSAction(), WAFAction(true)}, true},
+		{"bad status code", []PageRuleAction{ForwardingURLAction(307, "https://example.org")}, true},
+		{"negative ttl", []PageRuleAction{EdgeCacheTTLAction(-1)}, true},
+		{"duplicate", []PageRuleAction{WAFAction(true), WAFAction(false)}, true},
+		{"unknown", []PageRuleAction{{ID: "teleport", Value: "on"}}, true},
+		{"no actions", nil, true},
+	}
+	for _, tt := range tests {
+		err := PageRule{Targets: []PageRuleTarget{target}, Actions: tt.actions}.Validate()
+		if tt.wantErr {
+			assert.Error(t, err, tt.name)
+		} else {
+			assert.NoError(t, err, tt.name)
+		}
+	}
+
+	err := PageRule{Actions: []PageRuleAction{WAFAction(true)}}.Validate()
+	assert.Error(t, err)
+}
+
+func TestParsePageRuleAction(t *testing.T) {
+	tests := []struct {
+		id, value string
+		want      PageRuleAction
+	}{
+		{"cache_level", "aggressive", CacheLevelAction(CacheLevelAggressive)},
+		{"edge_cache_ttl", "3600", EdgeCacheTTLAction(3600)},
+		{"waf", "off", WAFAction(false)},
+		{"disable_apps", "", DisableAppsAction()},
+		{"forwarding_url", "301 https://example.com/$1", ForwardingURLAction(301, "https://example.com/$1")},
+		{"forwarding_url", "302,https://example.com/", ForwardingURLAction(302, "https://example.com/")},
+	}
+	for _, tt := range tests {
+		a, err := ParsePageRuleAction(tt.id, tt.value)
+		if assert.NoError(t, err, tt.id) {
+			assert.Equal(t, tt.want, a)
+		}
+	}
+
+	for _, bad := range [][2]string{
+		{"edge_cache_ttl", "an hour"},
+		{"waf", "maybe"},
+		{"disable_apps", "on"},
+		{"forwarding_url", "https://example.com/"},
+		{"teleport", "on"},
+	} {
+		_, err := ParsePageRuleAction(bad[0], bad[1])
+		assert.Error(t, err, bad[0])
+	}
+}
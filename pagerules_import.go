@@ -0,0 +1,291 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PageRuleDocument holds the Page Rules of a zone in a form suitable for
+// keeping under version control: without IDs, priorities or timestamps, and
+// ordered from the highest priority to the lowest.
+type PageRuleDocument struct {
+	Rules []PageRuleSpec `json:"rules"`
+}
+
+// PageRuleSpec describes a single Page Rule in a PageRuleDocument.
+type PageRuleSpec struct {
+	// Targets holds the URL patterns the rule matches.
+	Targets []string         `json:"targets"`
+	Actions []PageRuleAction `json:"actions"`
+	Status  string           `json:"status"`
+}
+
+// Page Rule import operations reported in PageRuleChange.
+const (
+	PageRuleUnchanged = "unchanged"
+	PageRuleCreate    = "create"
+	PageRuleUpdate    = "update"
+	PageRuleDelete    = "delete"
+)
+
+// PageRuleChange describes what an import does, or would do, to one rule.
+type PageRuleChange struct {
+	// Op is one of PageRuleUnchanged, PageRuleCreate, PageRuleUpdate or
+	// PageRuleDelete.
+	Op string
+	// Rule is the rule after the change, or the rule deleted.
+	Rule PageRule
+}
+
+// PageRuleImportResult describes the changes made by ImportPageRules.
+type PageRuleImportResult struct {
+	// Changes holds a change for every rule in the document, in order,
+	// followed by the deletions.
+	Changes []PageRuleChange
+	// Reordered is true if the priorities of the rules were changed.
+	Reordered bool
+}
+
+// NewPageRuleDocument returns a document for rules, such as those returned by
+// ListPageRules.
+func NewPageRuleDocument(rules []PageRule) PageRuleDocument {
+	sorted := make([]PageRule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority > sorted[j].Priority
+	})
+	doc := PageRuleDocument{Rules: []PageRuleSpec{}}
+	for _, r := range sorted {
+		spec := PageRuleSpec{Actions: r.Actions, Status: r.Status}
+		for _, t := range r.Targets {
+			spec.Targets = append(spec.Targets, t.Constraint.Value)
+		}
+		doc.Rules = append(doc.Rules, spec)
+	}
+	return doc
+}
+
+// ExportPageRules returns the Page Rules of a zone as a document.
+func (api *API) ExportPageRules(zoneID string) (PageRuleDocument, error) {
+	rules, err := api.ListPageRules(zoneID)
+	if err != nil {
+		return PageRuleDocument{}, err
+	}
+	return NewPageRuleDocument(rules), nil
+}
+
+// pageRule returns the Page Rule described by s.
+func (s PageRuleSpec) pageRule() PageRule {
+	r := PageRule{Actions: s.Actions, Status: s.Status}
+	if r.Status == "" {
+		r.Status = "active"
+	}
+	for _, pattern := range s.Targets {
+		t := PageRuleTarget{Target: "url"}
+		t.Constraint.Operator = "matches"
+		t.Constraint.Value = pattern
+		r.Targets = append(r.Targets, t)
+	}
+	return r
+}
+
+// RewriteHost returns a copy of the document with the host from replaced by
+// to in every URL pattern and forwarding URL. Subdomains and wildcards in
+// front of the host are kept, so rewriting example.com to
+// staging.example.com turns *.example.com/* into *.staging.example.com/*.
+func (d PageRuleDocument) RewriteHost(from, to string) PageRuleDocument {
+	out := PageRuleDocument{Rules: make([]PageRuleSpec, len(d.Rules))}
+	for i, spec := range d.Rules {
+		rewritten := PageRuleSpec{Status: spec.Status}
+		for _, t := range spec.Targets {
+			rewritten.Targets = append(rewritten.Targets, rewritePatternHost(t, from, to))
+		}
+		for _, a := range spec.Actions {
+			if f, err := a.ForwardingURL(); err == nil {
+				a = ForwardingURLAction(f.StatusCode, rewritePatternHost(f.URL, from, to))
+			}
+			rewritten.Actions = append(rewritten.Actions, a)
+		}
+		out.Rules[i] = rewritten
+	}
+	return out
+}
+
+// rewritePatternHost replaces the host from with to in a URL or URL pattern,
+// if the host is from or ends in from after a dot or wildcard.
+func rewritePatternHost(pattern, from, to string) string {
+	var scheme string
+	rest := pattern
+	if i := strings.Index(rest, "://"); i >= 0 {
+		scheme, rest = rest[:i+3], rest[i+3:]
+	}
+	host, path := rest, ""
+	if i := strings.IndexAny(rest, "/?"); i >= 0 {
+		host, path = rest[:i], rest[i:]
+	}
+	var port string
+	if i := strings.LastIndex(host, ":"); i >= 0 {
+		host, port = host[:i], host[i:]
+	}
+
+	lower := strings.ToLower(host)
+	from = strings.ToLower(from)
+	if !strings.HasSuffix(lower, from) {
+		return pattern
+	}
+	prefix := host[:len(host)-len(from)]
+	if prefix != "" && !strings.HasSuffix(prefix, ".") && !strings.HasSuffix(prefix, "*") {
+		return pattern
+	}
+	return scheme + prefix + to + port + path
+}
+
+// pageRuleTargetsKey identifies a rule by its URL patterns.
+func pageRuleTargetsKey(r PageRule) string {
+	var patterns []string
+	for _, t := range r.Targets {
+		patterns = append(patterns, t.Constraint.Value)
+	}
+	return strings.Join(patterns, "\x00")
+}
+
+// pageRuleSettingsEqual reports whether two rules have the same actions and
+// status, ignoring the order of the actions.
+func pageRuleSettingsEqual(a, b PageRule) bool {
+	if a.Status != b.Status || len(a.Actions) != len(b.Actions) {
+		return false
+	}
+	encode := func(actions []PageRuleAction) string {
+		sorted := make([]PageRuleAction, len(actions))
+		copy(sorted, actions)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+		b, _ := json.Marshal(sorted)
+		return string(b)
+	}
+	return encode(a.Actions) == encode(b.Actions)
+}
+
+// PlanPageRuleImport works out the changes needed to turn the existing rules
+// of a zone into those in doc. Existing rules are matched to the document by
+// their URL patterns. It also reports whether the rules need to be reordered
+// afterwards.
+func PlanPageRuleImport(existing []PageRule, doc PageRuleDocument) (changes []PageRuleChange, reorder bool) {
+	current := make([]PageRule, len(existing))
+	copy(current, existing)
+	sort.SliceStable(current, func(i, j int) bool {
+		return current[i].Priority > current[j].Priority
+	})
+	matched := make([]bool, len(current))
+
+	lastPriority := -1
+	for _, spec := range doc.Rules {
+		want := spec.pageRule()
+		key := pageRuleTargetsKey(want)
+		found := -1
+		for i, r := range current {
+			if !matched[i] && pageRuleTargetsKey(r) == key {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			changes = append(changes, PageRuleChange{Op: PageRuleCreate, Rule: want})
+			reorder = true
+			continue
+		}
+
+		matched[found] = true
+		have := current[found]
+		// Rules must appear in strictly decreasing priority to keep order.
+		if lastPriority >= 0 && have.Priority >= lastPriority {
+			reorder = true
+		}
+		lastPriority = have.Priority
+		if pageRuleSettingsEqual(have, want) {
+			changes = append(changes, PageRuleChange{Op: PageRuleUnchanged, Rule: have})
+			continue
+		}
+		want.ID = have.ID
+		want.Priority = have.Priority
+		changes = append(changes, PageRuleChange{Op: PageRuleUpdate, Rule: want})
+	}
+	for i, r := range current {
+		if !matched[i] {
+			changes = append(changes, PageRuleChange{Op: PageRuleDelete, Rule: r})
+		}
+	}
+	return changes, reorder
+}
+
+// ImportPageRules makes the Page Rules of a zone match doc: rules missing from
+// the zone are created, rules whose actions or status differ are updated,
+// rules not in the document are deleted and finally the rules are put in the
+// order of the document. Rules are matched by their URL patterns.
+//
+// If dryRun is true, no changes are made and the result describes what would
+// be done.
+func (api *API) ImportPageRules(zoneID string, doc PageRuleDocument, dryRun bool) (PageRuleImportResult, error) {
+	for _, spec := range doc.Rules {
+		if err := spec.pageRule().Validate(); err != nil {
+			return PageRuleImportResult{}, err
+		}
+	}
+	existing, err := api.ListPageRules(zoneID)
+	if err != nil {
+		return PageRuleImportResult{}, err
+	}
+	changes, reorder := PlanPageRuleImport(existing, doc)
+	result := PageRuleImportResult{Changes: changes, Reordered: reorder}
+	if dryRun {
+		return result, nil
+	}
+
+	// Delete first, so that the zone's Page Rule quota isn't exceeded.
+	for _, c := range changes {
+		if c.Op == PageRuleDelete {
+			if err := api.DeletePageRule(zoneID, c.Rule.ID); err != nil {
+				return result, errors.Wrapf(err, "could not delete Page Rule %s", c.Rule.ID)
+			}
+		}
+	}
+	var ids []string
+	for i, c := range changes {
+		switch c.Op {
+		case PageRuleCreate:
+			r, err := api.CreatePageRule(zoneID, c.Rule)
+			if err != nil {
+				return result, errors.Wrapf(err, "could not create Page Rule for %s", c.Rule.Targets[0].Constraint.Value)
+			}
+			result.Changes[i].Rule = r
+		case PageRuleUpdate:
+			r, err := api.UpdatePageRule(zoneID, c.Rule.ID, c.Rule)
+			if err != nil {
+				return result, errors.Wrapf(err, "could not update Page Rule %s", c.Rule.ID)
+			}
+			result.Changes[i].Rule = r
+		case PageRuleDelete:
+			continue
+		}
+		ids = append(ids, result.Changes[i].Rule.ID)
+	}
+
+	if reorder {
+		rules, err := api.ReorderPageRules(zoneID, ids)
+		if err != nil {
+			return result, errors.Wrap(err, "could not reorder Page Rules")
+		}
+		priorities := make(map[string]int)
+		for _, r := range rules {
+			priorities[r.ID] = r.Priority
+		}
+		for i, c := range result.Changes {
+			if p, ok := priorities[c.Rule.ID]; ok {
+				result.Changes[i].Rule.Priority = p
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,128 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPageRuleDocument(t *testing.T) {
+	rules := []PageRule{
+		testPageRule("a", 1, "active", "*example.com/*", CacheLevelAction(CacheLevelBasic)),
+		testPageRule("b", 2, "paused", "example.com/old/*", ForwardingURLAction(301, "https://example.com/new/$1")),
+	}
+	doc := NewPageRuleDocument(rules)
+
+	b, err := json.Marshal(doc)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"rules":[
+			{"targets":["example.com/old/*"],"actions":[{"id":"forwarding_url","value":{"status_code":301,"url":"https://example.com/new/$1"}}],"status":"paused"},
+			{"targets":["*example.com/*"],"actions":[{"id":"cache_level","value":"basic"}],"status":"active"}
+		]}`, string(b))
+	}
+}
+
+func TestPageRuleDocumentRewriteHost(t *testing.T) {
+	doc := PageRuleDocument{Rules: []PageRuleSpec{
+		{Targets: []string{"*example.com/*"}},
+		{Targets: []string{"https://www.example.com:8443/a?b"}},
+		{Targets: []string{"notexample.com/*"}},
+		{Targets: []string{"example.com"}, Actions: []PageRuleAction{ForwardingURLAction(301, "https://www.example.com/$1")}},
+	}}
+	out := doc.RewriteHost("example.com", "staging.example.com")
+	assert.Equal(t, []string{"*staging.example.com/*"}, out.Rules[0].Targets)
+	assert.Equal(t, []string{"https://www.staging.example.com:8443/a?b"}, out.Rules[1].Targets)
+	assert.Equal(t, []string{"notexample.com/*"}, out.Rules[2].Targets)
+	assert.Equal(t, []string{"staging.example.com"}, out.Rules[3].Targets)
+	f, _ := out.Rules[3].Actions[0].ForwardingURL()
+	assert.Equal(t, "https://www.staging.example.com/$1", f.URL)
+
+	// The original is unchanged.
+	assert.Equal(t, []string{"*example.com/*"}, doc.Rules[0].Targets)
+}
+
+func TestPlanPageRuleImport(t *testing.T) {
+	existing := []PageRule{
+		testPageRule("same", 3, "active", "example.com/a/*", CacheLevelAction(CacheLevelBasic)),
+		testPageRule("changed", 2, "active", "example.com/b/*", CacheLevelAction(CacheLevelBasic)),
+		testPageRule("extra", 1, "active", "example.com/c/*", CacheLevelAction(CacheLevelBasic)),
+	}
+	doc := PageRuleDocument{Rules: []PageRuleSpec{
+		{Targets: []string{"example.com/a/*"}, Actions: []PageRuleAction{CacheLevelAction(CacheLevelBasic)}, Status: "active"},
+		{Targets: []string{"example.com/b/*"}, Actions: []PageRuleAction{CacheLevelAction(CacheLevelBypass)}, Status: "active"},
+	}}
+	changes, reorder := PlanPageRuleImport(existing, doc)
+	assert.False(t, reorder)
+	if assert.Len(t, changes, 3) {
+		assert.Equal(t, PageRuleUnchanged, changes[0].Op)
+		assert.Equal(t, PageRuleUpdate, changes[1].Op)
+		assert.Equal(t, "changed", changes[1].Rule.ID)
+		assert.Equal(t, PageRuleDelete, changes[2].Op)
+		assert.Equal(t, "extra", changes[2].Rule.ID)
+	}
+
+	// Swapping the order of the document requires a reorder.
+	doc.Rules[0], doc.Rules[1] = doc.Rules[1], doc.Rules[0]
+	_, reorder = PlanPageRuleImport(existing, doc)
+	assert.True(t, reorder)
+}
+
+func TestImportPageRules(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls []string
+	mux.HandleFunc("/zones/abc/pagerules", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":[
+				{"id":"old","targets":[{"target":"url","constraint":{"operator":"matches","value":"example.com/old/*"}}],
+				 "actions":[{"id":"cache_level","value":"basic"}],"priority":1,"status":"active"}
+			]}`)
+		case "POST":
+			calls = append(calls, "create")
+			fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":
+				{"id":"new","targets":[{"target":"url","constraint":{"operator":"matches","value":"staging.example.com/*"}}],
+				 "actions":[{"id":"always_online","value":"on"}],"priority":2,"status":"active"}}`)
+		}
+	})
+	mux.HandleFunc("/zones/abc/pagerules/old", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		calls = append(calls, "delete")
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"id":"old"}}`)
+	})
+	mux.HandleFunc("/zones/abc/pagerules/priorities", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "reorder")
+		b, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `{"priorities":[{"id":"new","priority":1}]}`, string(b))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":[
+			{"id":"new","targets":[],"actions":[],"priority":1,"status":"active"}
+		]}`)
+	})
+
+	doc := PageRuleDocument{Rules: []PageRuleSpec{
+		{Targets: []string{"example.com/*"}, Actions: []PageRuleAction{AlwaysOnlineAction(true)}},
+	}}
+	doc = doc.RewriteHost("example.com", "staging.example.com")
+
+	result, err := client.ImportPageRules("abc", doc, true)
+	assert.NoError(t, err)
+	assert.Len(t, result.Changes, 2)
+	assert.Empty(t, calls)
+
+	result, err = client.ImportPageRules("abc", doc, false)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"delete", "create", "reorder"}, calls)
+		assert.True(t, result.Reordered)
+		assert.Equal(t, PageRuleCreate, result.Changes[0].Op)
+		assert.Equal(t, "new", result.Changes[0].Rule.ID)
+		assert.Equal(t, 1, result.Changes[0].Rule.Priority)
+	}
+}
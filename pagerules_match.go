@@ -0,0 +1,263 @@
+package cloudflare
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PageRuleMatcher finds the Page Rule that Cloudflare would apply to a URL,
+// without making any API requests.
+type PageRuleMatcher struct {
+	rules []compiledPageRule
+}
+
+// compiledPageRule is a Page Rule with its URL patterns compiled.
+type compiledPageRule struct {
+	rule     PageRule
+	patterns []pageRulePattern
+}
+
+// pageRulePattern is a compiled Page Rule URL pattern.
+type pageRulePattern struct {
+	re *regexp.Regexp
+	// port, if set, is the port the URL must be for. It is checked
+	// separately because a default port is left out of normalised URLs,
+	// and which port is the default depends on the scheme.
+	port string
+}
+
+// match returns the wildcard captures if the normalised URL u matches.
+func (p pageRulePattern) match(u string) ([]string, bool) {
+	sub := p.re.FindStringSubmatch(u)
+	if sub == nil {
+		return nil, false
+	}
+	if p.port != "" && pageRuleURLPort(u) != p.port {
+		return nil, false
+	}
+	return sub[1:], true
+}
+
+// PageRuleMatch describes a Page Rule matching a URL.
+type PageRuleMatch struct {
+	Rule PageRule
+	// Captures holds the text matched by each wildcard in the pattern, in
+	// order. These are what $1, $2 etc. refer to in a forwarding URL.
+	Captures []string
+	// ForwardingURL is the destination of the rule's forwarding_url action
+	// with wildcard references substituted, or empty if it has none.
+	ForwardingURL string
+}
+
+// NewPageRuleMatcher compiles the URL patterns of rules, such as those
+// returned by ListPageRules.
+func NewPageRuleMatcher(rules []PageRule) (*PageRuleMatcher, error) {
+	m := &PageRuleMatcher{}
+	for _, r := range rules {
+		c := compiledPageRule{rule: r}
+		for _, t := range r.Targets {
+			p, err := compilePageRulePattern(t.Constraint.Value)
+			if err != nil {
+				return nil, err
+			}
+			c.patterns = append(c.patterns, p)
+		}
+		m.rules = append(m.rules, c)
+	}
+	// Higher priorities take precedence.
+	sort.SliceStable(m.rules, func(i, j int) bool {
+		return m.rules[i].rule.Priority > m.rules[j].rule.Priority
+	})
+	return m, nil
+}
+
+// Match returns the Page Rule that applies to rawurl, or nil if none does.
+// Only the highest priority active rule that matches is applied.
+func (m *PageRuleMatcher) Match(rawurl string) (*PageRuleMatch, error) {
+	matches, err := m.MatchAll(rawurl)
+	if err != nil || len(matches) == 0 {
+		return nil, err
+	}
+	return &matches[0], nil
+}
+
+// MatchAll returns every active Page Rule that matches rawurl, highest
+// priority first. Rules after the first are shadowed by it.
+func (m *PageRuleMatcher) MatchAll(rawurl string) ([]PageRuleMatch, error) {
+	u, err := normalizePageRuleURL(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	var matches []PageRuleMatch
+	for _, c := range m.rules {
+		if c.rule.Status != "active" {
+			continue
+		}
+		for _, p := range c.patterns {
+			captures, ok := p.match(u)
+			if !ok {
+				continue
+			}
+			match := PageRuleMatch{Rule: c.rule, Captures: captures}
+			for _, a := range c.rule.Actions {
+				if f, err := a.ForwardingURL(); err == nil {
+					match.ForwardingURL = expandPageRuleCaptures(f.URL, match.Captures)
+				}
+			}
+			matches = append(matches, match)
+			break
+		}
+	}
+	return matches, nil
+}
+
+// pageRuleDefaultPorts maps schemes to the port Cloudflare assumes for them.
+var pageRuleDefaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// normalizePageRuleURL returns rawurl as scheme://host[:port]/path[?query],
+// with the scheme and host in lower case and the port only if it is not the
+// default for the scheme.
+func normalizePageRuleURL(rawurl string) (string, error) {
+	if !strings.Contains(rawurl, "://") {
+		rawurl = "http://" + rawurl
+	}
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid URL")
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if _, ok := pageRuleDefaultPorts[scheme]; !ok {
+		return "", errors.Errorf("invalid URL %q: scheme must be http or https", rawurl)
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return "", errors.Errorf("invalid URL %q: no host", rawurl)
+	}
+	if port := u.Port(); port != "" && port != pageRuleDefaultPorts[scheme] {
+		host += ":" + port
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return scheme + "://" + host + path, nil
+}
+
+// compilePageRulePattern turns a Page Rule URL pattern into a regular
+// expression matching URLs normalised by normalizePageRuleURL.
+//
+// A pattern without a scheme matches both http and https, and one without a
+// port matches only the default port. Each * matches any run of characters,
+// within the host part if it appears there, and is captured for $n
+// references. The host is matched case-insensitively.
+func compilePageRulePattern(pattern string) (pageRulePattern, error) {
+	rest := pattern
+	scheme := ""
+	if i := strings.Index(rest, "://"); i >= 0 {
+		scheme, rest = strings.ToLower(rest[:i]), rest[i+3:]
+	}
+	hostport, path := rest, "/"
+	if i := strings.IndexAny(rest, "/?"); i >= 0 {
+		hostport, path = rest[:i], rest[i:]
+		if path[0] == '?' {
+			path = "/" + path
+		}
+	}
+	hostport = strings.ToLower(hostport)
+	if hostport == "" {
+		return pageRulePattern{}, errors.Errorf("invalid Page Rule pattern %q: no host", pattern)
+	}
+
+	var p pageRulePattern
+	var b strings.Builder
+	b.WriteString("^")
+	// Without a scheme, whether normalizePageRuleURL leaves out an explicit
+	// default port depends on the URL's scheme, so the port is optional in
+	// the expression and checked by pageRulePattern.match instead.
+	var optionalPort string
+	if scheme == "" || scheme == "*" {
+		for _, port := range pageRuleDefaultPorts {
+			if strings.HasSuffix(hostport, ":"+port) {
+				hostport = strings.TrimSuffix(hostport, ":"+port)
+				optionalPort = "(?::" + port + ")?"
+				p.port = port
+			}
+		}
+	}
+	switch {
+	case scheme == "":
+		b.WriteString("(?:https?)")
+	case scheme == "*":
+		b.WriteString("(https?)")
+	default:
+		if _, ok := pageRuleDefaultPorts[scheme]; !ok {
+			return pageRulePattern{}, errors.Errorf("invalid Page Rule pattern %q: scheme must be http or https", pattern)
+		}
+		b.WriteString(scheme)
+		// An explicit default port is the same as none.
+		hostport = strings.TrimSuffix(hostport, ":"+pageRuleDefaultPorts[scheme])
+	}
+	b.WriteString("://")
+	writePageRuleWildcards(&b, hostport, "([^/?]*)")
+	b.WriteString(optionalPort)
+	writePageRuleWildcards(&b, path, "(.*)")
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return pageRulePattern{}, errors.Wrapf(err, "invalid Page Rule pattern %q", pattern)
+	}
+	p.re = re
+	return p, nil
+}
+
+// pageRuleURLPort returns the port of a URL normalised by
+// normalizePageRuleURL, which is the default for its scheme if none is given.
+func pageRuleURLPort(u string) string {
+	i := strings.Index(u, "://")
+	scheme, hostport := u[:i], u[i+3:]
+	if j := strings.IndexAny(hostport, "/?"); j >= 0 {
+		hostport = hostport[:j]
+	}
+	if j := strings.LastIndex(hostport, ":"); j >= 0 && !strings.HasSuffix(hostport, "]") {
+		return hostport[j+1:]
+	}
+	return pageRuleDefaultPorts[scheme]
+}
+
+// writePageRuleWildcards writes s to b as a regular expression, replacing
+// each * with wildcard.
+func writePageRuleWildcards(b *strings.Builder, s, wildcard string) {
+	for i, part := range strings.Split(s, "*") {
+		if i > 0 {
+			b.WriteString(wildcard)
+		}
+		b.WriteString(regexp.QuoteMeta(part))
+	}
+}
+
+// pageRuleCaptureRef matches $1, $2 etc. in a forwarding URL.
+var pageRuleCaptureRef = regexp.MustCompile(`\$([0-9]+)`)
+
+// expandPageRuleCaptures replaces references to wildcard captures in s.
+// References to captures that do not exist are left as they are.
+func expandPageRuleCaptures(s string, captures []string) string {
+	return pageRuleCaptureRef.ReplaceAllStringFunc(s, func(ref string) string {
+		n, err := strconv.Atoi(ref[1:])
+		if err != nil || n < 1 || n > len(captures) {
+			return ref
+		}
+		return captures[n-1]
+	})
+}
@@ -0,0 +1,89 @@
+package cloudflare
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testPageRule(id string, priority int, status, pattern string, actions ...PageRuleAction) PageRule {
+	target := PageRuleTarget{Target: "url"}
+	target.Constraint.Operator = "matches"
+	target.Constraint.Value = pattern
+	return PageRule{
+		ID:       id,
+		Targets:  []PageRuleTarget{target},
+		Actions:  actions,
+		Priority: priority,
+		Status:   status,
+	}
+}
+
+func TestPageRuleMatcher(t *testing.T) {
+	rules := []PageRule{
+		testPageRule("catchall", 1, "active", "*example.com/*", CacheLevelAction(CacheLevelBasic)),
+		testPageRule("images", 2, "active", "example.com/images/*", CacheLevelAction(CacheLevelCacheEverything)),
+		testPageRule("paused", 5, "paused", "example.com/*", CacheLevelAction(CacheLevelBypass)),
+		testPageRule("forward", 4, "active", "http://old.example.com/*/docs/*", ForwardingURLAction(301, "https://example.com/$2?from=$1&x=$3")),
+		testPageRule("port", 3, "active", "example.com:8080/*", CacheLevelAction(CacheLevelBypass)),
+		testPageRule("home", 6, "active", "https://example.com", AlwaysOnlineAction(true)),
+		testPageRule("shop", 7, "active", "shop.example.com:443/*", CacheLevelAction(CacheLevelBypass)),
+	}
+	m, err := NewPageRuleMatcher(rules)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		url     string
+		wantID  string
+		forward string
+	}{
+		{"https://example.com/images/logo.png", "images", ""},
+		{"http://Example.COM/images/logo.png", "images", ""},
+		{"https://example.com:443/images/a.png", "images", ""},
+		{"https://www.example.com/index.html", "catchall", ""},
+		{"https://example.com/?q=1", "catchall", ""},
+		{"https://example.com/", "home", ""},
+		{"https://example.com", "home", ""},
+		{"http://example.com", "catchall", ""},
+		{"http://example.com:8080/images/a.png", "port", ""},
+		{"http://old.example.com/v1/docs/intro.html", "forward", "https://example.com/intro.html?from=v1&x=$3"},
+		{"https://old.example.com/v1/docs/intro.html", "catchall", ""},
+		{"https://shop.example.com/cart", "shop", ""},
+		{"https://shop.example.com:443/cart", "shop", ""},
+		{"http://shop.example.com:443/cart", "shop", ""},
+		{"http://shop.example.com/cart", "catchall", ""},
+		{"https://shop.example.com:80/cart", "", ""},
+		{"https://example.org/", "", ""},
+	}
+	for _, tt := range tests {
+		match, err := m.Match(tt.url)
+		if !assert.NoError(t, err, tt.url) {
+			continue
+		}
+		if tt.wantID == "" {
+			assert.Nil(t, match, tt.url)
+			continue
+		}
+		if assert.NotNil(t, match, tt.url) {
+			assert.Equal(t, tt.wantID, match.Rule.ID, tt.url)
+			assert.Equal(t, tt.forward, match.ForwardingURL, tt.url)
+		}
+	}
+
+	all, err := m.MatchAll("https://example.com/images/logo.png")
+	if assert.NoError(t, err) && assert.Len(t, all, 2) {
+		assert.Equal(t, "images", all[0].Rule.ID)
+		assert.Equal(t, "catchall", all[1].Rule.ID)
+		assert.Equal(t, []string{"", "images/logo.png"}, all[1].Captures)
+	}
+
+	_, err = m.Match("ftp://example.com/")
+	assert.Error(t, err)
+}
+
+func TestNewPageRuleMatcherInvalid(t *testing.T) {
+	_, err := NewPageRuleMatcher([]PageRule{testPageRule("bad", 1, "active", "gopher://example.com/*")})
+	assert.Error(t, err)
+}
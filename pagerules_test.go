@@ -0,0 +1,106 @@
+package cloudflare
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReorderPageRules(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PATCH", r.Method, "Expected method 'PATCH', got %s", r.Method)
+		b, err := ioutil.ReadAll(r.Body)
+		defer r.Body.Close()
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{"priorities":[{"id":"b","priority":2},{"id":"a","priority":1}]}`, string(b))
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{"id": "a", "targets": [], "actions": [], "priority": 1, "status": "active"},
+				{"id": "b", "targets": [], "actions": [], "priority": 2, "status": "active"}
+			]
+		}`)
+	}
+	mux.HandleFunc("/zones/abc/pagerules/priorities", handler)
+
+	rules, err := client.ReorderPageRules("abc", []string{"b", "a"})
+	if assert.NoError(t, err) && assert.Len(t, rules, 2) {
+		assert.Equal(t, "b", rules[0].ID)
+		assert.Equal(t, "a", rules[1].ID)
+	}
+}
+
+func TestCreatePageRule(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method, "Expected method 'POST', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "9a7806061c88ada191ed06f989cc3dac",
+				"targets": [{"target": "url", "constraint": {"operator": "matches", "value": "*example.com/images/*"}}],
+				"actions": [{"id": "cache_level", "value": "cache_everything"}],
+				"priority": 1,
+				"status": "active",
+				"modified_on": "2014-01-01T05:20:00Z",
+				"created_on": "2014-01-01T05:20:00Z"
+			}
+		}`)
+	}
+	mux.HandleFunc("/zones/abc/pagerules", handler)
+
+	rule := testPageRule("", 0, "active", "*example.com/images/*", CacheLevelAction(CacheLevelCacheEverything))
+	created, _ := time.Parse(time.RFC3339, "2014-01-01T05:20:00Z")
+	want := rule
+	want.ID = "9a7806061c88ada191ed06f989cc3dac"
+	want.Priority = 1
+	want.CreatedOn = created
+	want.ModifiedOn = created
+
+	actual, err := client.CreatePageRule("abc", rule)
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestChangePageRuleNotSuccessful(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PATCH", r.Method, "Expected method 'PATCH', got %s", r.Method)
+		b, err := ioutil.ReadAll(r.Body)
+		defer r.Body.Close()
+		if assert.NoError(t, err) {
+			assert.Contains(t, string(b), `"status":"paused"`)
+			assert.NotContains(t, string(b), `"targets"`)
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": false,
+			"errors": [{"code": 1002, "message": "Invalid Page Rule identifier"}],
+			"messages": [],
+			"result": null
+		}`)
+	}
+	mux.HandleFunc("/zones/abc/pagerules/missing", handler)
+
+	_, err := client.ChangePageRule("abc", "missing", PageRule{Status: "paused"})
+	assert.EqualError(t, err, "error reported by API: Invalid Page Rule identifier (1002)")
+}
@@ -0,0 +1,158 @@
+package cloudflare
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// PurgeCacheMaxItems is the largest number of files, tags, hosts or prefixes
+// the API accepts in a single purge request.
+const PurgeCacheMaxItems = 30
+
+// PurgeBatchOptions configures PurgeCacheBatch.
+type PurgeBatchOptions struct {
+	// Concurrency is the maximum number of API requests in flight at once.
+	// Defaults to 4.
+	Concurrency int
+	// ChunkSize is the number of items sent in each request. Defaults to
+	// PurgeCacheMaxItems.
+	ChunkSize int
+	// Interval, if non-zero, is the minimum time between the start of two
+	// requests, to stay within the API's rate limits.
+	Interval time.Duration
+	// Progress, if non-nil, is called with the result of each request as soon
+	// as it is known. Calls are never made concurrently.
+	Progress func(PurgeBatchResult)
+}
+
+// PurgeBatchResult is the outcome of a single request in a batch purge.
+type PurgeBatchResult struct {
+	// Request holds the items purged by the request.
+	Request PurgeCacheRequest
+	// Err is nil if the purge succeeded.
+	Err error
+}
+
+// ChunkPurgeCacheRequest splits a purge request into requests of at most size
+// items each. Files, tags, hosts and prefixes are never mixed in the same
+// request, as the API doesn't allow it; Files and FilesWithHeaders count
+// together towards size.
+func ChunkPurgeCacheRequest(pcr PurgeCacheRequest, size int) []PurgeCacheRequest {
+	if size < 1 {
+		size = PurgeCacheMaxItems
+	}
+	var chunks []PurgeCacheRequest
+	nplain := len(pcr.Files)
+	nfiles := nplain + len(pcr.FilesWithHeaders)
+	for start := 0; start < nfiles; start += size {
+		end := start + size
+		if end > nfiles {
+			end = nfiles
+		}
+		var c PurgeCacheRequest
+		if start < nplain {
+			plainEnd := end
+			if plainEnd > nplain {
+				plainEnd = nplain
+			}
+			c.Files = pcr.Files[start:plainEnd]
+		}
+		if end > nplain {
+			headersStart := start - nplain
+			if headersStart < 0 {
+				headersStart = 0
+			}
+			c.FilesWithHeaders = pcr.FilesWithHeaders[headersStart : end-nplain]
+		}
+		chunks = append(chunks, c)
+	}
+	chunk := func(items []string, set func(*PurgeCacheRequest, []string)) {
+		for len(items) > 0 {
+			n := size
+			if n > len(items) {
+				n = len(items)
+			}
+			var c PurgeCacheRequest
+			set(&c, items[:n])
+			chunks = append(chunks, c)
+			items = items[n:]
+		}
+	}
+	chunk(pcr.Tags, func(c *PurgeCacheRequest, items []string) { c.Tags = items })
+	chunk(pcr.Hosts, func(c *PurgeCacheRequest, items []string) { c.Hosts = items })
+	chunk(pcr.Prefixes, func(c *PurgeCacheRequest, items []string) { c.Prefixes = items })
+	return chunks
+}
+
+// PurgeCacheBatch purges any number of files, tags, hosts or prefixes by
+// splitting pcr into requests the API accepts and running up to
+// opts.Concurrency of them at once.
+//
+// A failure does not stop the batch: every request gets a result, returned in
+// the order the items appear in pcr. The error is non-nil if any request
+// failed.
+func (api *API) PurgeCacheBatch(zoneID string, pcr PurgeCacheRequest, opts PurgeBatchOptions) ([]PurgeBatchResult, error) {
+	if pcr.Everything {
+		return nil, errors.New("use PurgeEverything to purge everything")
+	}
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = defaultBatchConcurrency
+	}
+	chunks := ChunkPurgeCacheRequest(pcr, opts.ChunkSize)
+
+	var throttle <-chan time.Time
+	if opts.Interval > 0 {
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	results := make([]PurgeBatchResult, len(chunks))
+	var mu sync.Mutex
+	record := func(i int, res PurgeBatchResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[i] = res
+		if opts.Progress != nil {
+			opts.Progress(res)
+		}
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				res, err := api.PurgeCache(zoneID, chunks[i])
+				if err == nil && !res.Success {
+					err = errors.New(errRequestNotSuccessful)
+				}
+				record(i, PurgeBatchResult{Request: chunks[i], Err: err})
+			}
+		}()
+	}
+	for i := range chunks {
+		if throttle != nil && i > 0 {
+			<-throttle
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var failed int
+	for _, res := range results {
+		if res.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, errors.Errorf("%d of %d purge requests failed", failed, len(chunks))
+	}
+	return results, nil
+}
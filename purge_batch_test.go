@@ -0,0 +1,86 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunkPurgeCacheRequest(t *testing.T) {
+	pcr := PurgeCacheRequest{
+		Files: []string{"a", "b", "c", "d", "e"},
+		Tags:  []string{"t1"},
+		Hosts: []string{"www.example.com"},
+	}
+	chunks := ChunkPurgeCacheRequest(pcr, 2)
+	assert.Equal(t, []PurgeCacheRequest{
+		{Files: []string{"a", "b"}},
+		{Files: []string{"c", "d"}},
+		{Files: []string{"e"}},
+		{Tags: []string{"t1"}},
+		{Hosts: []string{"www.example.com"}},
+	}, chunks)
+
+	assert.Len(t, ChunkPurgeCacheRequest(PurgeCacheRequest{Files: make([]string, 61)}, 0), 3)
+
+	// Files with and without headers share the limit.
+	withHeaders := []PurgeFile{{URL: "d"}, {URL: "e"}, {URL: "f"}}
+	chunks = ChunkPurgeCacheRequest(PurgeCacheRequest{Files: []string{"a", "b", "c"}, FilesWithHeaders: withHeaders}, 2)
+	assert.Equal(t, []PurgeCacheRequest{
+		{Files: []string{"a", "b"}},
+		{Files: []string{"c"}, FilesWithHeaders: withHeaders[:1]},
+		{FilesWithHeaders: withHeaders[1:]},
+	}, chunks)
+}
+
+func TestPurgeCacheBatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	var purged []string
+	mux.HandleFunc("/zones/abc/purge_cache", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		var pcr PurgeCacheRequest
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&pcr)) {
+			return
+		}
+		if len(pcr.Prefixes) > 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"success":false,"errors":[{"code":1000,"message":"Invalid prefix"}]}`)
+			return
+		}
+		mu.Lock()
+		purged = append(purged, pcr.Files...)
+		purged = append(purged, pcr.Tags...)
+		mu.Unlock()
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"id":"abc"}}`)
+	})
+
+	var progress int
+	pcr := PurgeCacheRequest{
+		Files:    []string{"https://example.com/a", "https://example.com/b", "https://example.com/c"},
+		Tags:     []string{"t1"},
+		Prefixes: []string{"example.com/bad"},
+	}
+	results, err := client.PurgeCacheBatch("abc", pcr, PurgeBatchOptions{
+		ChunkSize: 2,
+		Progress:  func(PurgeBatchResult) { progress++ },
+	})
+	assert.EqualError(t, err, "1 of 4 purge requests failed")
+	if assert.Len(t, results, 4) {
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, []string{"https://example.com/c"}, results[1].Request.Files)
+		assert.Error(t, results[3].Err)
+	}
+	assert.Equal(t, 4, progress)
+	assert.Len(t, purged, 4)
+
+	_, err = client.PurgeCacheBatch("abc", PurgeCacheRequest{Everything: true}, PurgeBatchOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,257 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// RateLimit is a rate limiting rule, which takes an action against clients
+// that make more than Threshold matching requests within Period seconds.
+type RateLimit struct {
+	ID          string                  `json:"id,omitempty"`
+	Disabled    bool                    `json:"disabled,omitempty"`
+	Description string                  `json:"description,omitempty"`
+	Match       RateLimitTrafficMatcher `json:"match"`
+	Bypass      []RateLimitKeyValue     `json:"bypass,omitempty"`
+	Threshold   int                     `json:"threshold"`
+	Period      int                     `json:"period"`
+	Action      RateLimitAction         `json:"action"`
+}
+
+// RateLimitTrafficMatcher contains the rules that determine whether a request
+// counts towards a rate limit.
+type RateLimitTrafficMatcher struct {
+	Request  RateLimitRequestMatcher  `json:"request"`
+	Response RateLimitResponseMatcher `json:"response"`
+}
+
+// RateLimitRequestMatcher matches requests by method, scheme and URL.
+//
+// Methods can contain GET, POST, PUT, DELETE, PATCH and HEAD, and Schemes can
+// contain HTTP and HTTPS; "_ALL_" matches all of them. URLPattern may
+// contain wildcards, such as example.com/api/*.
+type RateLimitRequestMatcher struct {
+	Methods    []string `json:"methods,omitempty"`
+	Schemes    []string `json:"schemes,omitempty"`
+	URLPattern string   `json:"url,omitempty"`
+}
+
+// RateLimitResponseMatcher matches requests by the response from the origin.
+//
+// If OriginTraffic is false, requests served from the cache are counted too.
+type RateLimitResponseMatcher struct {
+	Statuses      []int `json:"status,omitempty"`
+	OriginTraffic *bool `json:"origin_traffic,omitempty"`
+}
+
+// RateLimitKeyValue is a key and value, such as a URL that bypasses a rate
+// limit.
+type RateLimitKeyValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// RateLimitAction is the action taken when a rate limit is exceeded.
+//
+// Mode can be one of simulate, ban, challenge or js_challenge. Timeout, the
+// number of seconds the action lasts, and Response, a custom response to
+// send, can only be set for simulate and ban.
+type RateLimitAction struct {
+	Mode     string                   `json:"mode"`
+	Timeout  int                      `json:"timeout,omitempty"`
+	Response *RateLimitActionResponse `json:"response,omitempty"`
+}
+
+// RateLimitActionResponse is a custom response sent to clients that exceed a
+// rate limit. ContentType can be one of text/plain, text/xml or
+// application/json.
+type RateLimitActionResponse struct {
+	ContentType string `json:"content_type"`
+	Body        string `json:"body"`
+}
+
+// RateLimitResponse represents the response from the rate limit endpoint for
+// a single rule.
+type RateLimitResponse struct {
+	Response
+	Result RateLimit `json:"result"`
+}
+
+// RateLimitListResponse represents the response from the rate limits
+// endpoint.
+type RateLimitListResponse struct {
+	Response
+	Result     []RateLimit `json:"result"`
+	ResultInfo ResultInfo  `json:"result_info"`
+}
+
+// Limits on rate limit settings enforced by the API.
+const (
+	rateLimitMaxPeriod    = 86400
+	rateLimitMaxThreshold = 1000000
+	rateLimitMaxTimeout   = 86400
+)
+
+var (
+	rateLimitMethods = map[string]bool{
+		"GET": true, "POST": true, "PUT": true, "DELETE": true, "PATCH": true, "HEAD": true, "_ALL_": true,
+	}
+	rateLimitSchemes = map[string]bool{
+		"HTTP": true, "HTTPS": true, "_ALL_": true,
+	}
+	rateLimitContentTypes = map[string]bool{
+		"text/plain": true, "text/xml": true, "application/json": true,
+	}
+)
+
+// Validate checks a rate limit against the limits enforced by the API, so
+// that mistakes are caught before a request is made:
+//
+// Threshold must be between 1 and 1000000 and Period between 1 and 86400
+// seconds. For the simulate and ban actions Timeout is required, must be no
+// longer than 86400 seconds and must be at least as long as Period, since
+// a shorter ban would end before the period in which the threshold was
+// exceeded. The challenge actions can't have a timeout or custom response.
+func (rl RateLimit) Validate() error {
+	if rl.Threshold < 1 || rl.Threshold > rateLimitMaxThreshold {
+		return errors.Errorf("rate limit threshold %d out of range: must be between 1 and %d", rl.Threshold, rateLimitMaxThreshold)
+	}
+	if rl.Period < 1 || rl.Period > rateLimitMaxPeriod {
+		return errors.Errorf("rate limit period %d out of range: must be between 1 and %d seconds", rl.Period, rateLimitMaxPeriod)
+	}
+	if rl.Match.Request.URLPattern == "" {
+		return errors.New("rate limit requires a URL pattern to match")
+	}
+	for _, m := range rl.Match.Request.Methods {
+		if !rateLimitMethods[m] {
+			return errors.Errorf("invalid rate limit method %q", m)
+		}
+	}
+	for _, s := range rl.Match.Request.Schemes {
+		if !rateLimitSchemes[s] {
+			return errors.Errorf("invalid rate limit scheme %q", s)
+		}
+	}
+	for _, s := range rl.Match.Response.Statuses {
+		if s < 100 || s > 599 {
+			return errors.Errorf("invalid rate limit response status %d", s)
+		}
+	}
+
+	a := rl.Action
+	switch a.Mode {
+	case "simulate", "ban":
+		if a.Timeout < 1 || a.Timeout > rateLimitMaxTimeout {
+			return errors.Errorf("rate limit timeout %d out of range: must be between 1 and %d seconds", a.Timeout, rateLimitMaxTimeout)
+		}
+		if a.Timeout < rl.Period {
+			return errors.Errorf("rate limit timeout %d is shorter than its period %d", a.Timeout, rl.Period)
+		}
+		if a.Response != nil && !rateLimitContentTypes[a.Response.ContentType] {
+			return errors.Errorf("invalid rate limit response content type %q", a.Response.ContentType)
+		}
+	case "challenge", "js_challenge":
+		if a.Timeout != 0 {
+			return errors.Errorf("rate limit action %s can't have a timeout", a.Mode)
+		}
+		if a.Response != nil {
+			return errors.Errorf("rate limit action %s can't have a custom response", a.Mode)
+		}
+	default:
+		return errors.Errorf("invalid rate limit action %q", a.Mode)
+	}
+	return nil
+}
+
+// ListRateLimits returns all rate limits for a zone.
+//
+// API reference: https://api.cloudflare.com/#rate-limits-for-a-zone-list-rate-limits
+func (api *API) ListRateLimits(zoneID string) ([]RateLimit, error) {
+	v := url.Values{}
+	v.Set("per_page", "100")
+
+	var limits []RateLimit
+	page := 1
+	for {
+		v.Set("page", strconv.Itoa(page))
+		uri := "/zones/" + zoneID + "/rate_limits?" + v.Encode()
+		res, err := api.makeRequest("GET", uri, nil)
+		if err != nil {
+			return []RateLimit{}, errors.Wrap(err, errMakeRequestError)
+		}
+		var r RateLimitListResponse
+		err = json.Unmarshal(res, &r)
+		if err != nil {
+			return []RateLimit{}, errors.Wrap(err, errUnmarshalError)
+		}
+		if err := responseError(r.Response); err != nil {
+			return []RateLimit{}, err
+		}
+		limits = append(limits, r.Result...)
+		if r.ResultInfo.Page >= r.ResultInfo.TotalPages {
+			break
+		}
+		page++
+	}
+	return limits, nil
+}
+
+// RateLimit returns a single rate limit.
+//
+// API reference: https://api.cloudflare.com/#rate-limits-for-a-zone-rate-limit-details
+func (api *API) RateLimit(zoneID, limitID string) (RateLimit, error) {
+	uri := "/zones/" + zoneID + "/rate_limits/" + limitID
+	return api.rateLimitRequest("GET", uri, nil)
+}
+
+// CreateRateLimit creates a rate limit for a zone.
+//
+// API reference: https://api.cloudflare.com/#rate-limits-for-a-zone-create-a-ratelimit
+func (api *API) CreateRateLimit(zoneID string, limit RateLimit) (RateLimit, error) {
+	if err := limit.Validate(); err != nil {
+		return RateLimit{}, err
+	}
+	uri := "/zones/" + zoneID + "/rate_limits"
+	return api.rateLimitRequest("POST", uri, limit)
+}
+
+// UpdateRateLimit replaces a rate limit.
+//
+// API reference: https://api.cloudflare.com/#rate-limits-for-a-zone-update-rate-limit
+func (api *API) UpdateRateLimit(zoneID, limitID string, limit RateLimit) (RateLimit, error) {
+	if err := limit.Validate(); err != nil {
+		return RateLimit{}, err
+	}
+	uri := "/zones/" + zoneID + "/rate_limits/" + limitID
+	return api.rateLimitRequest("PUT", uri, limit)
+}
+
+// DeleteRateLimit deletes a rate limit.
+//
+// API reference: https://api.cloudflare.com/#rate-limits-for-a-zone-delete-rate-limit
+func (api *API) DeleteRateLimit(zoneID, limitID string) error {
+	uri := "/zones/" + zoneID + "/rate_limits/" + limitID
+	_, err := api.rateLimitRequest("DELETE", uri, nil)
+	return err
+}
+
+// rateLimitRequest makes a request to a rate limit endpoint returning a
+// single rate limit.
+func (api *API) rateLimitRequest(method, uri string, params interface{}) (RateLimit, error) {
+	res, err := api.makeRequest(method, uri, params)
+	if err != nil {
+		return RateLimit{}, errors.Wrap(err, errMakeRequestError)
+	}
+	var r RateLimitResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return RateLimit{}, errors.Wrap(err, errUnmarshalError)
+	}
+	if err := responseError(r.Response); err != nil {
+		return RateLimit{}, err
+	}
+	return r.Result, nil
+}
@@ -0,0 +1,105 @@
+package cloudflare
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRateLimit() RateLimit {
+	return RateLimit{
+		Description: "login",
+		Match: RateLimitTrafficMatcher{
+			Request: RateLimitRequestMatcher{
+				Methods:    []string{"POST"},
+				Schemes:    []string{"_ALL_"},
+				URLPattern: "example.com/login",
+			},
+			Response: RateLimitResponseMatcher{Statuses: []int{401, 403}},
+		},
+		Threshold: 5,
+		Period:    60,
+		Action:    RateLimitAction{Mode: "ban", Timeout: 600},
+	}
+}
+
+func TestRateLimitValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		edit  func(*RateLimit)
+		valid bool
+	}{
+		{"valid", func(rl *RateLimit) {}, true},
+		{"zero threshold", func(rl *RateLimit) { rl.Threshold = 0 }, false},
+		{"long period", func(rl *RateLimit) { rl.Period = 86401 }, false},
+		{"timeout shorter than period", func(rl *RateLimit) { rl.Action.Timeout = 30 }, false},
+		{"ban without timeout", func(rl *RateLimit) { rl.Action.Timeout = 0 }, false},
+		{"challenge", func(rl *RateLimit) { rl.Action = RateLimitAction{Mode: "challenge"} }, true},
+		{"challenge with timeout", func(rl *RateLimit) { rl.Action.Mode = "challenge" }, false},
+		{"bad content type", func(rl *RateLimit) {
+			rl.Action.Response = &RateLimitActionResponse{ContentType: "text/html", Body: "<p>slow down</p>"}
+		}, false},
+		{"bad method", func(rl *RateLimit) { rl.Match.Request.Methods = []string{"OPTIONS"} }, false},
+		{"bad status", func(rl *RateLimit) { rl.Match.Response.Statuses = []int{99} }, false},
+		{"no url", func(rl *RateLimit) { rl.Match.Request.URLPattern = "" }, false},
+	}
+	for _, tt := range tests {
+		rl := testRateLimit()
+		tt.edit(&rl)
+		err := rl.Validate()
+		if tt.valid {
+			assert.NoError(t, err, tt.name)
+		} else {
+			assert.Error(t, err, tt.name)
+		}
+	}
+}
+
+func TestCreateRateLimit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/rate_limits", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method, "Expected method 'POST', got %s", r.Method)
+		b, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `{"description":"login",
+			"match":{"request":{"methods":["POST"],"schemes":["_ALL_"],"url":"example.com/login"},"response":{"status":[401,403]}},
+			"threshold":5,"period":60,"action":{"mode":"ban","timeout":600}}`, string(b))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {
+			"id": "372e67954025e0ba6aaa6d586b9e0b59", "description": "login",
+			"match": {"request": {"methods": ["POST"], "schemes": ["_ALL_"], "url": "example.com/login"},
+			          "response": {"status": [401, 403], "origin_traffic": true}},
+			"threshold": 5, "period": 60, "action": {"mode": "ban", "timeout": 600}}}`)
+	})
+
+	rl, err := client.CreateRateLimit("abc", testRateLimit())
+	if assert.NoError(t, err) {
+		assert.Equal(t, "372e67954025e0ba6aaa6d586b9e0b59", rl.ID)
+		if assert.NotNil(t, rl.Match.Response.OriginTraffic) {
+			assert.True(t, *rl.Match.Response.OriginTraffic)
+		}
+	}
+}
+
+func TestListRateLimits(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/rate_limits", func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": [
+			{"id": "rl%s", "threshold": 10, "period": 1, "action": {"mode": "challenge"}}
+		], "result_info": {"page": %s, "per_page": 1, "total_pages": 2, "count": 1, "total_count": 2}}`, page, page)
+	})
+
+	limits, err := client.ListRateLimits("abc")
+	if assert.NoError(t, err) && assert.Len(t, limits, 2) {
+		assert.Equal(t, "rl1", limits[0].ID)
+		assert.Equal(t, "rl2", limits[1].ID)
+	}
+}
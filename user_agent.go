@@ -0,0 +1,158 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// UserAgentRule represents a User-Agent blocking rule, which blocks or
+// challenges requests with a given User-Agent header.
+//
+// Mode can be one of block, challenge or js_challenge.
+type UserAgentRule struct {
+	ID            string                     `json:"id,omitempty"`
+	Description   string                     `json:"description"`
+	Mode          string                     `json:"mode"`
+	Configuration UserAgentRuleConfiguration `json:"configuration"`
+	Paused        bool                       `json:"paused"`
+}
+
+// UserAgentRuleConfiguration is the User-Agent matched by a rule. Target is
+// always "ua" and Value is the exact User-Agent string.
+type UserAgentRuleConfiguration struct {
+	Target string `json:"target"`
+	Value  string `json:"value"`
+}
+
+// UserAgentRuleResponse represents the response from the User-Agent rule
+// endpoint for a single rule.
+type UserAgentRuleResponse struct {
+	Response
+	Result UserAgentRule `json:"result"`
+}
+
+// UserAgentRuleListResponse represents the response from the User-Agent rules
+// endpoint.
+type UserAgentRuleListResponse struct {
+	Response
+	Result     []UserAgentRule `json:"result"`
+	ResultInfo ResultInfo      `json:"result_info"`
+}
+
+// NewUserAgentRule returns a User-Agent rule matching ua.
+func NewUserAgentRule(mode, ua, description string) UserAgentRule {
+	return UserAgentRule{
+		Description:   description,
+		Mode:          mode,
+		Configuration: UserAgentRuleConfiguration{Target: "ua", Value: ua},
+	}
+}
+
+// Validate checks the mode and configuration of a User-Agent rule.
+func (r UserAgentRule) Validate() error {
+	switch r.Mode {
+	case AccessRuleBlock, AccessRuleChallenge, AccessRuleJSChallenge:
+	default:
+		return errors.Errorf("invalid User-Agent rule mode %q: must be block, challenge or js_challenge", r.Mode)
+	}
+	if r.Configuration.Target != "ua" {
+		return errors.Errorf("invalid User-Agent rule target %q: must be ua", r.Configuration.Target)
+	}
+	if r.Configuration.Value == "" {
+		return errors.New("User-Agent rule value is empty")
+	}
+	return nil
+}
+
+// ListUserAgentRules returns all User-Agent rules for a zone.
+//
+// API reference: https://api.cloudflare.com/#user-agent-blocking-rules-list-useragent-rules
+func (api *API) ListUserAgentRules(zoneID string) ([]UserAgentRule, error) {
+	v := url.Values{}
+	v.Set("per_page", "100")
+
+	var rules []UserAgentRule
+	page := 1
+	for {
+		v.Set("page", strconv.Itoa(page))
+		uri := "/zones/" + zoneID + "/firewall/ua_rules?" + v.Encode()
+		res, err := api.makeRequest("GET", uri, nil)
+		if err != nil {
+			return []UserAgentRule{}, errors.Wrap(err, errMakeRequestError)
+		}
+		var r UserAgentRuleListResponse
+		err = json.Unmarshal(res, &r)
+		if err != nil {
+			return []UserAgentRule{}, errors.Wrap(err, errUnmarshalError)
+		}
+		if err := responseError(r.Response); err != nil {
+			return []UserAgentRule{}, err
+		}
+		rules = append(rules, r.Result...)
+		if r.ResultInfo.Page >= r.ResultInfo.TotalPages {
+			break
+		}
+		page++
+	}
+	return rules, nil
+}
+
+// UserAgentRule returns a single User-Agent rule.
+//
+// API reference: https://api.cloudflare.com/#user-agent-blocking-rules-useragent-rule-details
+func (api *API) UserAgentRule(zoneID, id string) (UserAgentRule, error) {
+	uri := "/zones/" + zoneID + "/firewall/ua_rules/" + id
+	return api.userAgentRuleRequest("GET", uri, nil)
+}
+
+// CreateUserAgentRule creates a User-Agent rule for a zone.
+//
+// API reference: https://api.cloudflare.com/#user-agent-blocking-rules-create-useragent-rule
+func (api *API) CreateUserAgentRule(zoneID string, rule UserAgentRule) (UserAgentRule, error) {
+	if err := rule.Validate(); err != nil {
+		return UserAgentRule{}, err
+	}
+	uri := "/zones/" + zoneID + "/firewall/ua_rules"
+	return api.userAgentRuleRequest("POST", uri, rule)
+}
+
+// UpdateUserAgentRule replaces a User-Agent rule.
+//
+// API reference: https://api.cloudflare.com/#user-agent-blocking-rules-update-useragent-rule
+func (api *API) UpdateUserAgentRule(zoneID, id string, rule UserAgentRule) (UserAgentRule, error) {
+	if err := rule.Validate(); err != nil {
+		return UserAgentRule{}, err
+	}
+	uri := "/zones/" + zoneID + "/firewall/ua_rules/" + id
+	return api.userAgentRuleRequest("PUT", uri, rule)
+}
+
+// DeleteUserAgentRule deletes a User-Agent rule.
+//
+// API reference: https://api.cloudflare.com/#user-agent-blocking-rules-delete-useragent-rule
+func (api *API) DeleteUserAgentRule(zoneID, id string) error {
+	uri := "/zones/" + zoneID + "/firewall/ua_rules/" + id
+	_, err := api.userAgentRuleRequest("DELETE", uri, nil)
+	return err
+}
+
+// userAgentRuleRequest makes a request to a User-Agent rule endpoint
+// returning a single rule.
+func (api *API) userAgentRuleRequest(method, uri string, params interface{}) (UserAgentRule, error) {
+	res, err := api.makeRequest(method, uri, params)
+	if err != nil {
+		return UserAgentRule{}, errors.Wrap(err, errMakeRequestError)
+	}
+	var r UserAgentRuleResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return UserAgentRule{}, errors.Wrap(err, errUnmarshalError)
+	}
+	if err := responseError(r.Response); err != nil {
+		return UserAgentRule{}, err
+	}
+	return r.Result, nil
+}
@@ -0,0 +1,55 @@
+package cloudflare
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListUserAgentRules(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/firewall/ua_rules", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method, "Expected method 'GET', got %s", r.Method)
+		page := r.URL.Query().Get("page")
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": [
+			{"id": "ua%s", "description": "scraper", "mode": "block",
+			 "configuration": {"target": "ua", "value": "BadBot/1.0"}, "paused": false}
+		], "result_info": {"page": %s, "per_page": 1, "total_pages": 2, "count": 1, "total_count": 2}}`, page, page)
+	})
+
+	rules, err := client.ListUserAgentRules("abc")
+	if assert.NoError(t, err) && assert.Len(t, rules, 2) {
+		assert.Equal(t, "ua1", rules[0].ID)
+		assert.Equal(t, "BadBot/1.0", rules[1].Configuration.Value)
+	}
+}
+
+func TestCreateUserAgentRule(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/firewall/ua_rules", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method, "Expected method 'POST', got %s", r.Method)
+		b, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `{"description":"scraper","mode":"js_challenge",
+			"configuration":{"target":"ua","value":"BadBot/1.0"},"paused":false}`, string(b))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result":
+			{"id": "new", "description": "scraper", "mode": "js_challenge",
+			 "configuration": {"target": "ua", "value": "BadBot/1.0"}, "paused": false}}`)
+	})
+
+	rule, err := client.CreateUserAgentRule("abc", NewUserAgentRule(AccessRuleJSChallenge, "BadBot/1.0", "scraper"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "new", rule.ID)
+	}
+
+	_, err = client.CreateUserAgentRule("abc", NewUserAgentRule(AccessRuleWhitelist, "BadBot/1.0", ""))
+	assert.Error(t, err)
+}
@@ -2,6 +2,8 @@ package cloudflare
 
 import (
 	"encoding/json"
+	"net/url"
+	"strconv"
 
 	"github.com/pkg/errors"
 )
@@ -24,6 +26,48 @@ type WAFPackagesResponse struct {
 	ResultInfo ResultInfo   `json:"result_info"`
 }
 
+// WAFPackageResponse represents the response from the WAF package endpoint.
+type WAFPackageResponse struct {
+	Response
+	Result WAFPackage `json:"result"`
+}
+
+// WAFPackageOptions represents the settings of a WAF package that can be
+// changed. Empty fields are left unchanged.
+//
+// Sensitivity can be one of high, medium, low or off. ActionMode can be one
+// of simulate, block or challenge. Only packages with an anomaly detection
+// mode have these settings.
+type WAFPackageOptions struct {
+	Sensitivity string `json:"sensitivity,omitempty"`
+	ActionMode  string `json:"action_mode,omitempty"`
+}
+
+// WAFGroup represents a WAF rule group.
+type WAFGroup struct {
+	ID                 string   `json:"id"`
+	Name               string   `json:"name"`
+	Description        string   `json:"description"`
+	RulesCount         int      `json:"rules_count"`
+	ModifiedRulesCount int      `json:"modified_rules_count"`
+	PackageID          string   `json:"package_id"`
+	Mode               string   `json:"mode"`
+	AllowedModes       []string `json:"allowed_modes"`
+}
+
+// WAFGroupsResponse represents the response from the WAF groups endpoint.
+type WAFGroupsResponse struct {
+	Response
+	Result     []WAFGroup `json:"result"`
+	ResultInfo ResultInfo `json:"result_info"`
+}
+
+// WAFGroupResponse represents the response from the WAF group endpoint.
+type WAFGroupResponse struct {
+	Response
+	Result WAFGroup `json:"result"`
+}
+
 // WAFRule represents a WAF rule.
 type WAFRule struct {
 	ID          string `json:"id"`
@@ -46,52 +90,252 @@ type WAFRulesResponse struct {
 	ResultInfo ResultInfo `json:"result_info"`
 }
 
+// WAFRuleResponse represents the response from the WAF rule endpoint for a
+// single rule.
+type WAFRuleResponse struct {
+	Response
+	Result WAFRule `json:"result"`
+}
+
+// ModeAllowed reports whether mode is one of the rule's AllowedModes.
+func (r WAFRule) ModeAllowed(mode string) bool {
+	for _, m := range r.AllowedModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// ModeAllowed reports whether mode is one of the group's AllowedModes.
+func (g WAFGroup) ModeAllowed(mode string) bool {
+	for _, m := range g.AllowedModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
 // ListWAFPackages returns a slice of the WAF packages for the given zone.
+//
+// API reference: https://api.cloudflare.com/#waf-rule-packages-list-firewall-packages
 func (api *API) ListWAFPackages(zoneID string) ([]WAFPackage, error) {
-	var p WAFPackagesResponse
+	v := url.Values{}
+	v.Set("per_page", "100")
+
 	var packages []WAFPackage
-	var res []byte
-	var err error
-	uri := "/zones/" + zoneID + "/firewall/waf/packages"
-	res, err = api.makeRequest("GET", uri, nil)
+	page := 1
+	for {
+		v.Set("page", strconv.Itoa(page))
+		uri := "/zones/" + zoneID + "/firewall/waf/packages?" + v.Encode()
+		res, err := api.makeRequest("GET", uri, nil)
+		if err != nil {
+			return []WAFPackage{}, errors.Wrap(err, errMakeRequestError)
+		}
+		var p WAFPackagesResponse
+		err = json.Unmarshal(res, &p)
+		if err != nil {
+			return []WAFPackage{}, errors.Wrap(err, errUnmarshalError)
+		}
+		if err := responseError(p.Response); err != nil {
+			return []WAFPackage{}, err
+		}
+		packages = append(packages, p.Result...)
+		if p.ResultInfo.Page >= p.ResultInfo.TotalPages {
+			break
+		}
+		page++
+	}
+	return packages, nil
+}
+
+// WAFPackage returns a single WAF package.
+//
+// API reference: https://api.cloudflare.com/#waf-rule-packages-firewall-package-details
+func (api *API) WAFPackage(zoneID, packageID string) (WAFPackage, error) {
+	uri := "/zones/" + zoneID + "/firewall/waf/packages/" + packageID
+	return api.wafPackageRequest("GET", uri, nil)
+}
+
+// UpdateWAFPackage changes the sensitivity and action mode of a WAF package
+// and returns the updated package.
+//
+// API reference: https://api.cloudflare.com/#waf-rule-packages-edit-firewall-package
+func (api *API) UpdateWAFPackage(zoneID, packageID string, opts WAFPackageOptions) (WAFPackage, error) {
+	uri := "/zones/" + zoneID + "/firewall/waf/packages/" + packageID
+	return api.wafPackageRequest("PATCH", uri, opts)
+}
+
+// wafPackageRequest makes a request to a WAF package endpoint returning a
+// single package.
+func (api *API) wafPackageRequest(method, uri string, params interface{}) (WAFPackage, error) {
+	res, err := api.makeRequest(method, uri, params)
 	if err != nil {
-		return []WAFPackage{}, errors.Wrap(err, errMakeRequestError)
+		return WAFPackage{}, errors.Wrap(err, errMakeRequestError)
 	}
-	err = json.Unmarshal(res, &p)
+	var r WAFPackageResponse
+	err = json.Unmarshal(res, &r)
 	if err != nil {
-		return []WAFPackage{}, errors.Wrap(err, errUnmarshalError)
+		return WAFPackage{}, errors.Wrap(err, errUnmarshalError)
 	}
-	if !p.Success {
-		// TODO: Provide an actual error message instead of always returning nil
-		return []WAFPackage{}, err
+	if err := responseError(r.Response); err != nil {
+		return WAFPackage{}, err
 	}
-	for pi := range p.Result {
-		packages = append(packages, p.Result[pi])
+	return r.Result, nil
+}
+
+// ListWAFGroups returns a slice of the rule groups in the given WAF package.
+//
+// API reference: https://api.cloudflare.com/#waf-rule-groups-list-rule-groups
+func (api *API) ListWAFGroups(zoneID, packageID string) ([]WAFGroup, error) {
+	v := url.Values{}
+	v.Set("per_page", "100")
+
+	var groups []WAFGroup
+	page := 1
+	for {
+		v.Set("page", strconv.Itoa(page))
+		uri := "/zones/" + zoneID + "/firewall/waf/packages/" + packageID + "/groups?" + v.Encode()
+		res, err := api.makeRequest("GET", uri, nil)
+		if err != nil {
+			return []WAFGroup{}, errors.Wrap(err, errMakeRequestError)
+		}
+		var r WAFGroupsResponse
+		err = json.Unmarshal(res, &r)
+		if err != nil {
+			return []WAFGroup{}, errors.Wrap(err, errUnmarshalError)
+		}
+		if err := responseError(r.Response); err != nil {
+			return []WAFGroup{}, err
+		}
+		groups = append(groups, r.Result...)
+		if r.ResultInfo.Page >= r.ResultInfo.TotalPages {
+			break
+		}
+		page++
 	}
-	return packages, nil
+	return groups, nil
+}
+
+// WAFGroup returns a single rule group in the given WAF package.
+//
+// API reference: https://api.cloudflare.com/#waf-rule-groups-rule-group-details
+func (api *API) WAFGroup(zoneID, packageID, groupID string) (WAFGroup, error) {
+	uri := "/zones/" + zoneID + "/firewall/waf/packages/" + packageID + "/groups/" + groupID
+	return api.wafGroupRequest("GET", uri, nil)
+}
+
+// UpdateWAFGroup turns a rule group on or off. mode must be "on" or "off".
+// The updated group is returned.
+//
+// API reference: https://api.cloudflare.com/#waf-rule-groups-edit-rule-group
+func (api *API) UpdateWAFGroup(zoneID, packageID, groupID, mode string) (WAFGroup, error) {
+	if mode != "on" && mode != "off" {
+		return WAFGroup{}, errors.Errorf("invalid WAF group mode %q: must be on or off", mode)
+	}
+	params := struct {
+		Mode string `json:"mode"`
+	}{mode}
+	uri := "/zones/" + zoneID + "/firewall/waf/packages/" + packageID + "/groups/" + groupID
+	return api.wafGroupRequest("PATCH", uri, params)
+}
+
+// wafGroupRequest makes a request to a WAF group endpoint returning a single
+// group.
+func (api *API) wafGroupRequest(method, uri string, params interface{}) (WAFGroup, error) {
+	res, err := api.makeRequest(method, uri, params)
+	if err != nil {
+		return WAFGroup{}, errors.Wrap(err, errMakeRequestError)
+	}
+	var r WAFGroupResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return WAFGroup{}, errors.Wrap(err, errUnmarshalError)
+	}
+	if err := responseError(r.Response); err != nil {
+		return WAFGroup{}, err
+	}
+	return r.Result, nil
 }
 
 // ListWAFRules returns a slice of the WAF rules for the given WAF package.
+//
+// API reference: https://api.cloudflare.com/#waf-rules-list-rules
 func (api *API) ListWAFRules(zoneID, packageID string) ([]WAFRule, error) {
-	var r WAFRulesResponse
+	v := url.Values{}
+	v.Set("per_page", "100")
+
 	var rules []WAFRule
-	var res []byte
-	var err error
-	uri := "/zones/" + zoneID + "/firewall/waf/packages/" + packageID + "/rules"
-	res, err = api.makeRequest("GET", uri, nil)
+	page := 1
+	for {
+		v.Set("page", strconv.Itoa(page))
+		uri := "/zones/" + zoneID + "/firewall/waf/packages/" + packageID + "/rules?" + v.Encode()
+		res, err := api.makeRequest("GET", uri, nil)
+		if err != nil {
+			return []WAFRule{}, errors.Wrap(err, errMakeRequestError)
+		}
+		var r WAFRulesResponse
+		err = json.Unmarshal(res, &r)
+		if err != nil {
+			return []WAFRule{}, errors.Wrap(err, errUnmarshalError)
+		}
+		if err := responseError(r.Response); err != nil {
+			return []WAFRule{}, err
+		}
+		rules = append(rules, r.Result...)
+		if r.ResultInfo.Page >= r.ResultInfo.TotalPages {
+			break
+		}
+		page++
+	}
+	return rules, nil
+}
+
+// WAFRule returns a single WAF rule.
+//
+// API reference: https://api.cloudflare.com/#waf-rules-rule-details
+func (api *API) WAFRule(zoneID, packageID, ruleID string) (WAFRule, error) {
+	uri := "/zones/" + zoneID + "/firewall/waf/packages/" + packageID + "/rules/" + ruleID
+	return api.wafRuleRequest("GET", uri, nil)
+}
+
+// UpdateWAFRule changes the mode of a WAF rule and returns the updated rule.
+// The rule is fetched first and mode must be one of its AllowedModes: rules
+// in anomaly detection packages only allow "on" and "off", while traditional
+// rules allow modes such as "default", "disable", "simulate", "block" and
+// "challenge".
+//
+// API reference: https://api.cloudflare.com/#waf-rules-edit-rule
+func (api *API) UpdateWAFRule(zoneID, packageID, ruleID, mode string) (WAFRule, error) {
+	rule, err := api.WAFRule(zoneID, packageID, ruleID)
 	if err != nil {
-		return []WAFRule{}, errors.Wrap(err, errMakeRequestError)
+		return WAFRule{}, err
 	}
-	err = json.Unmarshal(res, &r)
+	if !rule.ModeAllowed(mode) {
+		return WAFRule{}, errors.Errorf("invalid mode %q for WAF rule %s: must be one of %v", mode, ruleID, rule.AllowedModes)
+	}
+	params := struct {
+		Mode string `json:"mode"`
+	}{mode}
+	uri := "/zones/" + zoneID + "/firewall/waf/packages/" + packageID + "/rules/" + ruleID
+	return api.wafRuleRequest("PATCH", uri, params)
+}
+
+// wafRuleRequest makes a request to a WAF rule endpoint returning a single
+// rule.
+func (api *API) wafRuleRequest(method, uri string, params interface{}) (WAFRule, error) {
+	res, err := api.makeRequest(method, uri, params)
 	if err != nil {
-		return []WAFRule{}, errors.Wrap(err, errUnmarshalError)
+		return WAFRule{}, errors.Wrap(err, errMakeRequestError)
 	}
-	if !r.Success {
-		// TODO: Provide an actual error message instead of always returning nil
-		return []WAFRule{}, err
+	var r WAFRuleResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return WAFRule{}, errors.Wrap(err, errUnmarshalError)
 	}
-	for ri := range r.Result {
-		rules = append(rules, r.Result[ri])
+	if err := responseError(r.Response); err != nil {
+		return WAFRule{}, err
 	}
-	return rules, nil
+	return r.Result, nil
 }
@@ -0,0 +1,285 @@
+package cloudflare
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// WAFBaseline records the WAF configuration of a set of zones, so that it can
+// be kept as evidence for audits and compared with the live configuration
+// later.
+type WAFBaseline struct {
+	Zones []WAFZoneBaseline `json:"zones"`
+}
+
+// WAFZoneBaseline records the WAF configuration of a single zone.
+type WAFZoneBaseline struct {
+	Zone     string               `json:"zone"`
+	ZoneID   string               `json:"zone_id"`
+	Packages []WAFPackageBaseline `json:"packages"`
+}
+
+// WAFPackageBaseline records the settings of a WAF package, the mode of each
+// of its rule groups and the rules whose mode differs from their default.
+type WAFPackageBaseline struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Sensitivity string             `json:"sensitivity,omitempty"`
+	ActionMode  string             `json:"action_mode,omitempty"`
+	Groups      []WAFGroupBaseline `json:"groups"`
+	Rules       []WAFRuleBaseline  `json:"rules"`
+}
+
+// WAFGroupBaseline records the mode of a WAF rule group.
+type WAFGroupBaseline struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Mode string `json:"mode"`
+}
+
+// WAFRuleBaseline records the mode of a WAF rule that isn't in its default
+// mode.
+type WAFRuleBaseline struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Mode        string `json:"mode"`
+	DefaultMode string `json:"default_mode"`
+}
+
+// WAFDrift describes a difference between a WAF baseline and the live
+// configuration.
+type WAFDrift struct {
+	Zone string
+	// Package is the name of the WAF package; it is empty for zones.
+	Package string
+	// Kind is one of "zone", "package", "group" or "rule".
+	Kind string
+	// ID and Name identify the package, group or rule.
+	ID   string
+	Name string
+	// Setting is the setting that changed: "mode", "sensitivity",
+	// "action_mode" or, for zones, packages and groups that were added or
+	// removed, "presence".
+	Setting  string
+	Baseline string
+	Current  string
+}
+
+func (d WAFDrift) String() string {
+	var what string
+	switch d.Kind {
+	case "zone":
+		what = "zone"
+	case "package":
+		what = fmt.Sprintf("package %s", d.Name)
+	default:
+		what = fmt.Sprintf("%s %s (%s) in %s", d.Kind, d.ID, d.Name, d.Package)
+	}
+	return fmt.Sprintf("%s: %s %s changed from %q to %q", d.Zone, what, d.Setting, d.Baseline, d.Current)
+}
+
+// wafRuleModeDefault is the mode reported for rules in their default mode.
+const wafRuleModeDefault = "default"
+
+// wafRuleMode returns the mode of a rule, or "default" if the rule is in its
+// default mode.
+func wafRuleMode(mode, defaultMode string) string {
+	if mode == defaultMode {
+		return wafRuleModeDefault
+	}
+	return mode
+}
+
+// WAFZoneBaseline returns the WAF configuration of a zone.
+func (api *API) WAFZoneBaseline(zoneID string) (WAFZoneBaseline, error) {
+	zb := WAFZoneBaseline{ZoneID: zoneID, Packages: []WAFPackageBaseline{}}
+	packages, err := api.ListWAFPackages(zoneID)
+	if err != nil {
+		return zb, err
+	}
+	for _, p := range packages {
+		pb := WAFPackageBaseline{
+			ID:          p.ID,
+			Name:        p.Name,
+			Sensitivity: p.Sensitivity,
+			ActionMode:  p.ActionMode,
+			Groups:      []WAFGroupBaseline{},
+			Rules:       []WAFRuleBaseline{},
+		}
+		groups, err := api.ListWAFGroups(zoneID, p.ID)
+		if err != nil {
+			return zb, errors.Wrapf(err, "could not list groups of WAF package %s", p.ID)
+		}
+		for _, g := range groups {
+			pb.Groups = append(pb.Groups, WAFGroupBaseline{ID: g.ID, Name: g.Name, Mode: g.Mode})
+		}
+		rules, err := api.ListWAFRules(zoneID, p.ID)
+		if err != nil {
+			return zb, errors.Wrapf(err, "could not list rules of WAF package %s", p.ID)
+		}
+		for _, r := range rules {
+			if wafRuleMode(r.Mode, r.DefaultMode) == wafRuleModeDefault {
+				continue
+			}
+			pb.Rules = append(pb.Rules, WAFRuleBaseline{
+				ID:          r.ID,
+				Description: r.Description,
+				Mode:        r.Mode,
+				DefaultMode: r.DefaultMode,
+			})
+		}
+		sort.Slice(pb.Groups, func(i, j int) bool { return pb.Groups[i].ID < pb.Groups[j].ID })
+		sort.Slice(pb.Rules, func(i, j int) bool { return pb.Rules[i].ID < pb.Rules[j].ID })
+		zb.Packages = append(zb.Packages, pb)
+	}
+	sort.Slice(zb.Packages, func(i, j int) bool { return zb.Packages[i].ID < zb.Packages[j].ID })
+	return zb, nil
+}
+
+// ExportWAFBaseline returns the WAF configuration of every zone in the
+// account.
+func (api *API) ExportWAFBaseline() (WAFBaseline, error) {
+	zones, err := api.ListZones()
+	if err != nil {
+		return WAFBaseline{}, err
+	}
+	b := WAFBaseline{Zones: []WAFZoneBaseline{}}
+	for _, z := range zones {
+		zb, err := api.WAFZoneBaseline(z.ID)
+		if err != nil {
+			return WAFBaseline{}, errors.Wrapf(err, "could not export WAF configuration of %s", z.Name)
+		}
+		zb.Zone = z.Name
+		b.Zones = append(b.Zones, zb)
+	}
+	sort.Slice(b.Zones, func(i, j int) bool { return b.Zones[i].Zone < b.Zones[j].Zone })
+	return b, nil
+}
+
+// WAFDriftFromBaseline compares the WAF configuration of every zone in the
+// account with baseline and returns the differences.
+func (api *API) WAFDriftFromBaseline(baseline WAFBaseline) ([]WAFDrift, error) {
+	current, err := api.ExportWAFBaseline()
+	if err != nil {
+		return nil, err
+	}
+	return CompareWAFBaselines(baseline, current), nil
+}
+
+// CompareWAFBaselines returns the differences between two WAF baselines.
+// Zones are matched by name, and packages, groups and rules by ID. Rules
+// missing from either baseline are taken to be in their default mode.
+func CompareWAFBaselines(baseline, current WAFBaseline) []WAFDrift {
+	var drift []WAFDrift
+	currentZones := make(map[string]WAFZoneBaseline)
+	for _, z := range current.Zones {
+		currentZones[z.Zone] = z
+	}
+	seen := make(map[string]bool)
+	for _, bz := range baseline.Zones {
+		seen[bz.Zone] = true
+		cz, ok := currentZones[bz.Zone]
+		if !ok {
+			drift = append(drift, WAFDrift{Zone: bz.Zone, Kind: "zone", ID: bz.ZoneID, Name: bz.Zone,
+				Setting: "presence", Baseline: "present", Current: "missing"})
+			continue
+		}
+		drift = append(drift, compareWAFZoneBaselines(bz, cz)...)
+	}
+	for _, cz := range current.Zones {
+		if !seen[cz.Zone] {
+			drift = append(drift, WAFDrift{Zone: cz.Zone, Kind: "zone", ID: cz.ZoneID, Name: cz.Zone,
+				Setting: "presence", Baseline: "missing", Current: "present"})
+		}
+	}
+	return drift
+}
+
+// compareWAFZoneBaselines returns the differences between two baselines of
+// the same zone.
+func compareWAFZoneBaselines(b, c WAFZoneBaseline) []WAFDrift {
+	var drift []WAFDrift
+	currentPackages := make(map[string]WAFPackageBaseline)
+	for _, p := range c.Packages {
+		currentPackages[p.ID] = p
+	}
+	seen := make(map[string]bool)
+	for _, bp := range b.Packages {
+		seen[bp.ID] = true
+		d := WAFDrift{Zone: b.Zone, Package: bp.Name, Kind: "package", ID: bp.ID, Name: bp.Name}
+		cp, ok := currentPackages[bp.ID]
+		if !ok {
+			d.Setting, d.Baseline, d.Current = "presence", "present", "missing"
+			drift = append(drift, d)
+			continue
+		}
+		if bp.Sensitivity != cp.Sensitivity {
+			d.Setting, d.Baseline, d.Current = "sensitivity", bp.Sensitivity, cp.Sensitivity
+			drift = append(drift, d)
+		}
+		if bp.ActionMode != cp.ActionMode {
+			d.Setting, d.Baseline, d.Current = "action_mode", bp.ActionMode, cp.ActionMode
+			drift = append(drift, d)
+		}
+
+		currentGroups := make(map[string]WAFGroupBaseline)
+		for _, g := range cp.Groups {
+			currentGroups[g.ID] = g
+		}
+		for _, bg := range bp.Groups {
+			d := WAFDrift{Zone: b.Zone, Package: bp.Name, Kind: "group", ID: bg.ID, Name: bg.Name, Setting: "mode"}
+			cg, ok := currentGroups[bg.ID]
+			if !ok {
+				d.Setting, d.Baseline, d.Current = "presence", "present", "missing"
+				drift = append(drift, d)
+			} else if bg.Mode != cg.Mode {
+				d.Baseline, d.Current = bg.Mode, cg.Mode
+				drift = append(drift, d)
+			}
+		}
+		baselineGroups := make(map[string]bool)
+		for _, bg := range bp.Groups {
+			baselineGroups[bg.ID] = true
+		}
+		for _, cg := range cp.Groups {
+			if !baselineGroups[cg.ID] {
+				drift = append(drift, WAFDrift{Zone: b.Zone, Package: bp.Name, Kind: "group", ID: cg.ID, Name: cg.Name,
+					Setting: "presence", Baseline: "missing", Current: "present"})
+			}
+		}
+
+		currentRules := make(map[string]WAFRuleBaseline)
+		for _, r := range cp.Rules {
+			currentRules[r.ID] = r
+		}
+		for _, br := range bp.Rules {
+			d := WAFDrift{Zone: b.Zone, Package: bp.Name, Kind: "rule", ID: br.ID, Name: br.Description, Setting: "mode",
+				Baseline: wafRuleMode(br.Mode, br.DefaultMode), Current: wafRuleModeDefault}
+			if cr, ok := currentRules[br.ID]; ok {
+				d.Current = wafRuleMode(cr.Mode, cr.DefaultMode)
+			}
+			if d.Baseline != d.Current {
+				drift = append(drift, d)
+			}
+		}
+		baselineRules := make(map[string]bool)
+		for _, br := range bp.Rules {
+			baselineRules[br.ID] = true
+		}
+		for _, cr := range cp.Rules {
+			if !baselineRules[cr.ID] && wafRuleMode(cr.Mode, cr.DefaultMode) != wafRuleModeDefault {
+				drift = append(drift, WAFDrift{Zone: b.Zone, Package: bp.Name, Kind: "rule", ID: cr.ID, Name: cr.Description,
+					Setting: "mode", Baseline: wafRuleModeDefault, Current: cr.Mode})
+			}
+		}
+	}
+	for _, cp := range c.Packages {
+		if !seen[cp.ID] {
+			drift = append(drift, WAFDrift{Zone: b.Zone, Package: cp.Name, Kind: "package", ID: cp.ID, Name: cp.Name,
+				Setting: "presence", Baseline: "missing", Current: "present"})
+		}
+	}
+	return drift
+}
@@ -0,0 +1,125 @@
+package cloudflare
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWAFZoneBaseline(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/firewall/waf/packages", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": [
+			{"id": "pkg", "name": "OWASP", "detection_mode": "anomaly", "sensitivity": "high", "action_mode": "block"}
+		], "result_info": {"page": 1, "total_pages": 1}}`)
+	})
+	mux.HandleFunc("/zones/abc/firewall/waf/packages/pkg/groups", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": [
+			{"id": "grp", "name": "OWASP Generic Attacks", "mode": "on", "allowed_modes": ["on", "off"]}
+		], "result_info": {"page": 1, "total_pages": 1}}`)
+	})
+	mux.HandleFunc("/zones/abc/firewall/waf/packages/pkg/rules", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": [
+			{"id": "960000", "description": "Unchanged", "mode": "on", "default_mode": "on"},
+			{"id": "960001", "description": "Turned off", "mode": "off", "default_mode": "on"}
+		], "result_info": {"page": 1, "total_pages": 1}}`)
+	})
+
+	zb, err := client.WAFZoneBaseline("abc")
+	if assert.NoError(t, err) && assert.Len(t, zb.Packages, 1) {
+		p := zb.Packages[0]
+		assert.Equal(t, "high", p.Sensitivity)
+		assert.Equal(t, []WAFGroupBaseline{{ID: "grp", Name: "OWASP Generic Attacks", Mode: "on"}}, p.Groups)
+		assert.Equal(t, []WAFRuleBaseline{{ID: "960001", Description: "Turned off", Mode: "off", DefaultMode: "on"}}, p.Rules)
+	}
+}
+
+func TestExportWAFBaseline(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// The zones span two pages; zones on the second page must be included.
+	mux.HandleFunc("/zones", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.URL.Query().Get("page") {
+		case "1":
+			fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": [
+				{"id": "abc", "name": "example.com"}
+			], "result_info": {"page": 1, "total_pages": 2}}`)
+		case "2":
+			fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": [
+				{"id": "def", "name": "example.net"}
+			], "result_info": {"page": 2, "total_pages": 2}}`)
+		default:
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+		}
+	})
+	for _, id := range []string{"abc", "def"} {
+		mux.HandleFunc("/zones/"+id+"/firewall/waf/packages", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("content-type", "application/json")
+			fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": [],
+				"result_info": {"page": 1, "total_pages": 1}}`)
+		})
+	}
+
+	b, err := client.ExportWAFBaseline()
+	if assert.NoError(t, err) && assert.Len(t, b.Zones, 2) {
+		assert.Equal(t, "example.com", b.Zones[0].Zone)
+		assert.Equal(t, "example.net", b.Zones[1].Zone)
+		assert.Equal(t, "def", b.Zones[1].ZoneID)
+	}
+}
+
+func TestCompareWAFBaselines(t *testing.T) {
+	baseline := WAFBaseline{Zones: []WAFZoneBaseline{
+		{Zone: "example.com", Packages: []WAFPackageBaseline{{
+			ID: "pkg", Name: "OWASP", Sensitivity: "high", ActionMode: "block",
+			Groups: []WAFGroupBaseline{{ID: "grp", Name: "Generic", Mode: "on"}},
+			Rules:  []WAFRuleBaseline{{ID: "1", Mode: "block", DefaultMode: "challenge"}},
+		}}},
+		{Zone: "gone.com"},
+	}}
+	current := WAFBaseline{Zones: []WAFZoneBaseline{
+		{Zone: "example.com", Packages: []WAFPackageBaseline{{
+			ID: "pkg", Name: "OWASP", Sensitivity: "low", ActionMode: "block",
+			Groups: []WAFGroupBaseline{{ID: "grp", Name: "Generic", Mode: "off"}, {ID: "new", Name: "Added", Mode: "on"}},
+			Rules:  []WAFRuleBaseline{{ID: "2", Mode: "disable", DefaultMode: "challenge"}},
+		}}},
+	}}
+
+	drift := CompareWAFBaselines(baseline, current)
+	if assert.Len(t, drift, 6) {
+		assert.Equal(t, "sensitivity", drift[0].Setting)
+		assert.Equal(t, "high", drift[0].Baseline)
+		assert.Equal(t, "low", drift[0].Current)
+
+		assert.Equal(t, "group", drift[1].Kind)
+		assert.Equal(t, "off", drift[1].Current)
+
+		assert.Equal(t, "group", drift[2].Kind)
+		assert.Equal(t, "new", drift[2].ID)
+		assert.Equal(t, "presence", drift[2].Setting)
+		assert.Equal(t, "missing", drift[2].Baseline)
+		assert.Equal(t, "present", drift[2].Current)
+
+		assert.Equal(t, "1", drift[3].ID)
+		assert.Equal(t, "block", drift[3].Baseline)
+		assert.Equal(t, "default", drift[3].Current)
+
+		assert.Equal(t, "2", drift[4].ID)
+		assert.Equal(t, "default", drift[4].Baseline)
+		assert.Equal(t, "disable", drift[4].Current)
+
+		assert.Equal(t, "zone", drift[5].Kind)
+		assert.Equal(t, "gone.com", drift[5].Zone)
+	}
+
+	assert.Empty(t, CompareWAFBaselines(baseline, baseline))
+}
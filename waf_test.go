@@ -0,0 +1,133 @@
+package cloudflare
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListWAFRulesPagination(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method, "Expected method 'GET', got %s", r.Method)
+		page := r.URL.Query().Get("page")
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{"id": "rule%s", "mode": "on", "default_mode": "on", "allowed_modes": ["on", "off"]}
+			],
+			"result_info": {"page": %s, "per_page": 1, "total_pages": 2, "count": 1, "total_count": 2}
+		}`, page, page)
+	}
+	mux.HandleFunc("/zones/abc/firewall/waf/packages/pkg/rules", handler)
+
+	rules, err := client.ListWAFRules("abc", "pkg")
+	if assert.NoError(t, err) && assert.Len(t, rules, 2) {
+		assert.Equal(t, "rule1", rules[0].ID)
+		assert.Equal(t, "rule2", rules[1].ID)
+	}
+}
+
+func TestListWAFPackagesNotSuccessful(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/firewall/waf/packages", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": false, "errors": [{"code": 1003, "message": "Invalid or missing zone id."}], "messages": [], "result": null}`)
+	})
+
+	_, err := client.ListWAFPackages("abc")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Invalid or missing zone id. (1003)")
+	}
+}
+
+func TestUpdateWAFPackage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/firewall/waf/packages/pkg", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PATCH", r.Method, "Expected method 'PATCH', got %s", r.Method)
+		b, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `{"sensitivity":"low","action_mode":"challenge"}`, string(b))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result":
+			{"id": "pkg", "name": "OWASP ModSecurity Core Rule Set", "detection_mode": "anomaly", "sensitivity": "low", "action_mode": "challenge"}}`)
+	})
+
+	p, err := client.UpdateWAFPackage("abc", "pkg", WAFPackageOptions{Sensitivity: "low", ActionMode: "challenge"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "low", p.Sensitivity)
+		assert.Equal(t, "challenge", p.ActionMode)
+	}
+}
+
+func TestWAFGroups(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/firewall/waf/packages/pkg/groups", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": [
+			{"id": "grp", "name": "Project Honey Pot", "rules_count": 10, "modified_rules_count": 2, "package_id": "pkg", "mode": "on", "allowed_modes": ["on", "off"]}
+		], "result_info": {"page": 1, "per_page": 100, "total_pages": 1, "count": 1, "total_count": 1}}`)
+	})
+	mux.HandleFunc("/zones/abc/firewall/waf/packages/pkg/groups/grp", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PATCH", r.Method, "Expected method 'PATCH', got %s", r.Method)
+		b, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `{"mode":"off"}`, string(b))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result":
+			{"id": "grp", "name": "Project Honey Pot", "package_id": "pkg", "mode": "off", "allowed_modes": ["on", "off"]}}`)
+	})
+
+	groups, err := client.ListWAFGroups("abc", "pkg")
+	if assert.NoError(t, err) && assert.Len(t, groups, 1) {
+		assert.Equal(t, 2, groups[0].ModifiedRulesCount)
+	}
+
+	g, err := client.UpdateWAFGroup("abc", "pkg", "grp", "off")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "off", g.Mode)
+	}
+
+	_, err = client.UpdateWAFGroup("abc", "pkg", "grp", "block")
+	assert.Error(t, err)
+}
+
+func TestUpdateWAFRule(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var patched bool
+	mux.HandleFunc("/zones/abc/firewall/waf/packages/pkg/rules/100000", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		mode := "default"
+		if r.Method == "PATCH" {
+			patched = true
+			mode = "block"
+		}
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result":
+			{"id": "100000", "package_id": "pkg", "mode": %q, "default_mode": "challenge",
+			 "allowed_modes": ["default", "disable", "simulate", "block", "challenge"]}}`, mode)
+	})
+
+	_, err := client.UpdateWAFRule("abc", "pkg", "100000", "on")
+	assert.Error(t, err)
+	assert.False(t, patched)
+
+	rule, err := client.UpdateWAFRule("abc", "pkg", "100000", "block")
+	if assert.NoError(t, err) {
+		assert.True(t, patched)
+		assert.Equal(t, "block", rule.Mode)
+	}
+}
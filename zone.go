@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
@@ -86,7 +87,8 @@ type ZoneResponse struct {
 // ZonesResponse represents the response from the Zone endpoint containing an array of zones.
 type ZonesResponse struct {
 	Response
-	Result []Zone `json:"result"`
+	Result     []Zone     `json:"result"`
+	ResultInfo ResultInfo `json:"result_info"`
 }
 
 // ZoneIDResponse represents the response from the Zone endpoint, containing only a zone ID.
@@ -164,43 +166,62 @@ type zoneAnalyticsColocationResponse struct {
 
 // ZoneAnalytics contains analytics data for a zone.
 type ZoneAnalytics struct {
-	Since    time.Time `json:"since"`
-	Until    time.Time `json:"until"`
-	Requests struct {
-		All         int            `json:"all"`
-		Cached      int            `json:"cached"`
-		Uncached    int            `json:"uncached"`
-		ContentType map[string]int `json:"content_type"`
-		Country     map[string]int `json:"country"`
-		SSL         struct {
-			Encrypted   int `json:"encrypted"`
-			Unencrypted int `json:"unencrypted"`
-		} `json:"ssl"`
-		HTTPStatus map[string]int `json:"http_status"`
-	} `json:"requests"`
-	Bandwidth struct {
-		All         int            `json:"all"`
-		Cached      int            `json:"cached"`
-		Uncached    int            `json:"uncached"`
-		ContentType map[string]int `json:"content_type"`
-		Country     map[string]int `json:"country"`
-		SSL         struct {
-			Encrypted   int `json:"encrypted"`
-			Unencrypted int `json:"unencrypted"`
-		} `json:"ssl"`
-	} `json:"bandwidth"`
-	Threats struct {
-		All     int            `json:"all"`
-		Country map[string]int `json:"country"`
-		Type    map[string]int `json:"type"`
-	} `json:"threats"`
-	Pageviews struct {
-		All           int            `json:"all"`
-		SearchEngines map[string]int `json:"search_engines"`
-	} `json:"pageviews"`
-	Uniques struct {
-		All int `json:"all"`
-	}
+	Since     time.Time              `json:"since"`
+	Until     time.Time              `json:"until"`
+	Requests  ZoneAnalyticsRequests  `json:"requests"`
+	Bandwidth ZoneAnalyticsBandwidth `json:"bandwidth"`
+	Threats   ZoneAnalyticsThreats   `json:"threats"`
+	Pageviews ZoneAnalyticsPageviews `json:"pageviews"`
+	Uniques   ZoneAnalyticsUniques   `json:"uniques"`
+}
+
+// ZoneAnalyticsRequests contains the number of requests served, broken down
+// by type.
+type ZoneAnalyticsRequests struct {
+	All         int            `json:"all"`
+	Cached      int            `json:"cached"`
+	Uncached    int            `json:"uncached"`
+	ContentType map[string]int `json:"content_type"`
+	Country     map[string]int `json:"country"`
+	SSL         struct {
+		Encrypted   int `json:"encrypted"`
+		Unencrypted int `json:"unencrypted"`
+	} `json:"ssl"`
+	HTTPStatus map[string]int `json:"http_status"`
+}
+
+// ZoneAnalyticsBandwidth contains the number of bytes served, broken down by
+// type.
+type ZoneAnalyticsBandwidth struct {
+	All         int            `json:"all"`
+	Cached      int            `json:"cached"`
+	Uncached    int            `json:"uncached"`
+	ContentType map[string]int `json:"content_type"`
+	Country     map[string]int `json:"country"`
+	SSL         struct {
+		Encrypted   int `json:"encrypted"`
+		Unencrypted int `json:"unencrypted"`
+	} `json:"ssl"`
+}
+
+// ZoneAnalyticsThreats contains the number of threats seen, by country and
+// type.
+type ZoneAnalyticsThreats struct {
+	All     int            `json:"all"`
+	Country map[string]int `json:"country"`
+	Type    map[string]int `json:"type"`
+}
+
+// ZoneAnalyticsPageviews contains the number of pageviews, including those
+// by search engines.
+type ZoneAnalyticsPageviews struct {
+	All           int            `json:"all"`
+	SearchEngines map[string]int `json:"search_engines"`
+}
+
+// ZoneAnalyticsUniques contains the number of unique visitors.
+type ZoneAnalyticsUniques struct {
+	All int `json:"all"`
 }
 
 // ZoneAnalyticsOptions represents the optional parameters in Zone Analytics
@@ -212,10 +233,78 @@ type ZoneAnalyticsOptions struct {
 }
 
 // PurgeCacheRequest represents the request format made to the purge endpoint.
+//
+// Files cached under a custom cache key, such as per device type or language,
+// are purged by giving the headers that form the key in FilesWithHeaders. Both
+// Files and FilesWithHeaders are sent in the "files" list of the request.
 type PurgeCacheRequest struct {
-	Everything bool     `json:"purge_everything,omitempty"`
-	Files      []string `json:"files,omitempty"`
-	Tags       []string `json:"tags,omitempty"`
+	Everything       bool
+	Files            []string
+	FilesWithHeaders []PurgeFile
+	Tags             []string
+	Hosts            []string
+	Prefixes         []string
+}
+
+// PurgeFile is a file to purge along with the request headers of its cache
+// key.
+type PurgeFile struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// purgeCacheRequestJSON is the form of PurgeCacheRequest sent to the API,
+// where files are either URLs or PurgeFile objects.
+type purgeCacheRequestJSON struct {
+	Everything bool              `json:"purge_everything,omitempty"`
+	Files      []json.RawMessage `json:"files,omitempty"`
+	Tags       []string          `json:"tags,omitempty"`
+	Hosts      []string          `json:"hosts,omitempty"`
+	Prefixes   []string          `json:"prefixes,omitempty"`
+}
+
+// MarshalJSON encodes the request, with Files as plain URLs followed by
+// FilesWithHeaders as objects.
+func (r PurgeCacheRequest) MarshalJSON() ([]byte, error) {
+	j := purgeCacheRequestJSON{Everything: r.Everything, Tags: r.Tags, Hosts: r.Hosts, Prefixes: r.Prefixes}
+	for _, f := range r.Files {
+		b, err := json.Marshal(f)
+		if err != nil {
+			return nil, err
+		}
+		j.Files = append(j.Files, b)
+	}
+	for _, f := range r.FilesWithHeaders {
+		b, err := json.Marshal(f)
+		if err != nil {
+			return nil, err
+		}
+		j.Files = append(j.Files, b)
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes a request, putting files given as plain URLs in
+// Files and those given as objects in FilesWithHeaders.
+func (r *PurgeCacheRequest) UnmarshalJSON(data []byte) error {
+	var j purgeCacheRequestJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*r = PurgeCacheRequest{Everything: j.Everything, Tags: j.Tags, Hosts: j.Hosts, Prefixes: j.Prefixes}
+	for _, b := range j.Files {
+		var u string
+		if err := json.Unmarshal(b, &u); err == nil {
+			r.Files = append(r.Files, u)
+			continue
+		}
+		var f PurgeFile
+		if err := json.Unmarshal(b, &f); err != nil {
+			return err
+		}
+		r.FilesWithHeaders = append(r.FilesWithHeaders, f)
+	}
+	return nil
 }
 
 // PurgeCacheResponse represents the response from the purge endpoint.
@@ -308,18 +397,27 @@ func (api *API) ListZones(z ...string) ([]Zone, error) {
 			}
 		}
 	} else {
-		// TODO: Paginate here. We only grab the first page of results.
-		// Could do this concurrently after the first request by creating a
-		// sync.WaitGroup or just a channel + workers.
-		res, err = api.makeRequest("GET", "/zones", nil)
-		if err != nil {
-			return []Zone{}, errors.Wrap(err, errMakeRequestError)
-		}
-		err = json.Unmarshal(res, &r)
-		if err != nil {
-			return []Zone{}, errors.Wrap(err, errUnmarshalError)
+		v.Set("per_page", "50")
+		page := 1
+		for {
+			v.Set("page", strconv.Itoa(page))
+			res, err = api.makeRequest("GET", "/zones?"+v.Encode(), nil)
+			if err != nil {
+				return []Zone{}, errors.Wrap(err, errMakeRequestError)
+			}
+			err = json.Unmarshal(res, &r)
+			if err != nil {
+				return []Zone{}, errors.Wrap(err, errUnmarshalError)
+			}
+			if err := responseError(r.Response); err != nil {
+				return []Zone{}, err
+			}
+			zones = append(zones, r.Result...)
+			if r.ResultInfo.Page >= r.ResultInfo.TotalPages {
+				break
+			}
+			page++
 		}
-		zones = r.Result
 	}
 
 	return zones, nil
@@ -410,7 +508,7 @@ func (api *API) EditZone(zoneID string, zoneOpts ZoneOptions) (Zone, error) {
 // API reference: https://api.cloudflare.com/#zone-purge-all-files
 func (api *API) PurgeEverything(zoneID string) (PurgeCacheResponse, error) {
 	uri := "/zones/" + zoneID + "/purge_cache"
-	res, err := api.makeRequest("DELETE", uri, PurgeCacheRequest{true, nil, nil})
+	res, err := api.makeRequest("DELETE", uri, PurgeCacheRequest{Everything: true})
 	if err != nil {
 		return PurgeCacheResponse{}, errors.Wrap(err, errMakeRequestError)
 	}
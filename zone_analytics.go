@@ -0,0 +1,187 @@
+package cloudflare
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ZoneAnalyticsLast returns options requesting the analytics for the period
+// of length d ending now, such as ZoneAnalyticsLast(24 * time.Hour) for the
+// last day. The times are rounded down to the minute.
+func ZoneAnalyticsLast(d time.Duration) ZoneAnalyticsOptions {
+	until := time.Now().UTC().Truncate(time.Minute)
+	since := until.Add(-d)
+	return ZoneAnalyticsOptions{Since: &since, Until: &until}
+}
+
+// CacheHitRatio returns the fraction of requests served from cache, or 0 if
+// there were no requests.
+func (a ZoneAnalytics) CacheHitRatio() float64 {
+	if a.Requests.All == 0 {
+		return 0
+	}
+	return float64(a.Requests.Cached) / float64(a.Requests.All)
+}
+
+// BandwidthSaved returns the fraction of bandwidth served from cache, which
+// did not have to be fetched from the origin, or 0 if nothing was served.
+// The number of bytes saved is Bandwidth.Cached.
+func (a ZoneAnalytics) BandwidthSaved() float64 {
+	if a.Bandwidth.All == 0 {
+		return 0
+	}
+	return float64(a.Bandwidth.Cached) / float64(a.Bandwidth.All)
+}
+
+// Add returns the sum of two periods of analytics, covering both periods.
+// Unique visitors cannot be combined exactly, so Uniques is the sum of both,
+// which is an upper bound.
+func (a ZoneAnalytics) Add(b ZoneAnalytics) ZoneAnalytics {
+	sum := ZoneAnalytics{Since: a.Since, Until: a.Until}
+	if sum.Since.IsZero() || (!b.Since.IsZero() && b.Since.Before(sum.Since)) {
+		sum.Since = b.Since
+	}
+	if b.Until.After(sum.Until) {
+		sum.Until = b.Until
+	}
+
+	sum.Requests.All = a.Requests.All + b.Requests.All
+	sum.Requests.Cached = a.Requests.Cached + b.Requests.Cached
+	sum.Requests.Uncached = a.Requests.Uncached + b.Requests.Uncached
+	sum.Requests.ContentType = addAnalyticsCounts(a.Requests.ContentType, b.Requests.ContentType)
+	sum.Requests.Country = addAnalyticsCounts(a.Requests.Country, b.Requests.Country)
+	sum.Requests.SSL.Encrypted = a.Requests.SSL.Encrypted + b.Requests.SSL.Encrypted
+	sum.Requests.SSL.Unencrypted = a.Requests.SSL.Unencrypted + b.Requests.SSL.Unencrypted
+	sum.Requests.HTTPStatus = addAnalyticsCounts(a.Requests.HTTPStatus, b.Requests.HTTPStatus)
+
+	sum.Bandwidth.All = a.Bandwidth.All + b.Bandwidth.All
+	sum.Bandwidth.Cached = a.Bandwidth.Cached + b.Bandwidth.Cached
+	sum.Bandwidth.Uncached = a.Bandwidth.Uncached + b.Bandwidth.Uncached
+	sum.Bandwidth.ContentType = addAnalyticsCounts(a.Bandwidth.ContentType, b.Bandwidth.ContentType)
+	sum.Bandwidth.Country = addAnalyticsCounts(a.Bandwidth.Country, b.Bandwidth.Country)
+	sum.Bandwidth.SSL.Encrypted = a.Bandwidth.SSL.Encrypted + b.Bandwidth.SSL.Encrypted
+	sum.Bandwidth.SSL.Unencrypted = a.Bandwidth.SSL.Unencrypted + b.Bandwidth.SSL.Unencrypted
+
+	sum.Threats.All = a.Threats.All + b.Threats.All
+	sum.Threats.Country = addAnalyticsCounts(a.Threats.Country, b.Threats.Country)
+	sum.Threats.Type = addAnalyticsCounts(a.Threats.Type, b.Threats.Type)
+
+	sum.Pageviews.All = a.Pageviews.All + b.Pageviews.All
+	sum.Pageviews.SearchEngines = addAnalyticsCounts(a.Pageviews.SearchEngines, b.Pageviews.SearchEngines)
+
+	sum.Uniques.All = a.Uniques.All + b.Uniques.All
+	return sum
+}
+
+// addAnalyticsCounts returns a new map with the counts of a and b added, or
+// nil if both are empty.
+func addAnalyticsCounts(a, b map[string]int) map[string]int {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+	sum := make(map[string]int, len(a))
+	for k, n := range a {
+		sum[k] = n
+	}
+	for k, n := range b {
+		sum[k] += n
+	}
+	return sum
+}
+
+// SumZoneAnalytics adds up a timeseries into a single period.
+func SumZoneAnalytics(timeseries []ZoneAnalytics) ZoneAnalytics {
+	var sum ZoneAnalytics
+	for _, a := range timeseries {
+		sum = sum.Add(a)
+	}
+	return sum
+}
+
+// ResampleZoneAnalytics combines the periods of a timeseries into periods of
+// the given interval, such as turning hourly data into daily data. Periods
+// are grouped by their start time, rounded down to the interval since the
+// zero time, and the result is ordered by time.
+func ResampleZoneAnalytics(timeseries []ZoneAnalytics, interval time.Duration) []ZoneAnalytics {
+	buckets := make(map[time.Time]ZoneAnalytics)
+	for _, a := range timeseries {
+		start := a.Since.Truncate(interval)
+		b, ok := buckets[start]
+		if !ok {
+			b = ZoneAnalytics{Since: start}
+		}
+		buckets[start] = b.Add(a)
+	}
+	resampled := make([]ZoneAnalytics, 0, len(buckets))
+	for start, b := range buckets {
+		if end := start.Add(interval); b.Until.Before(end) {
+			b.Until = end
+		}
+		resampled = append(resampled, b)
+	}
+	sort.Slice(resampled, func(i, j int) bool {
+		return resampled[i].Since.Before(resampled[j].Since)
+	})
+	return resampled
+}
+
+// zoneAnalyticsCSVHeader names the columns written by WriteZoneAnalyticsCSV.
+var zoneAnalyticsCSVHeader = []string{
+	"since", "until",
+	"requests_all", "requests_cached", "requests_uncached", "requests_encrypted", "requests_unencrypted",
+	"bandwidth_all", "bandwidth_cached", "bandwidth_uncached", "bandwidth_encrypted", "bandwidth_unencrypted",
+	"threats_all", "pageviews_all", "uniques_all",
+	"cache_hit_ratio", "bandwidth_saved",
+}
+
+// WriteZoneAnalyticsCSV writes a timeseries as CSV, with a header and a row
+// for every period. Only the totals of each block are written; the breakdowns
+// by country, content type and so on are left out.
+func WriteZoneAnalyticsCSV(w io.Writer, timeseries []ZoneAnalytics) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(zoneAnalyticsCSVHeader); err != nil {
+		return err
+	}
+	for _, a := range timeseries {
+		row := []string{
+			a.Since.Format(time.RFC3339),
+			a.Until.Format(time.RFC3339),
+			strconv.Itoa(a.Requests.All),
+			strconv.Itoa(a.Requests.Cached),
+			strconv.Itoa(a.Requests.Uncached),
+			strconv.Itoa(a.Requests.SSL.Encrypted),
+			strconv.Itoa(a.Requests.SSL.Unencrypted),
+			strconv.Itoa(a.Bandwidth.All),
+			strconv.Itoa(a.Bandwidth.Cached),
+			strconv.Itoa(a.Bandwidth.Uncached),
+			strconv.Itoa(a.Bandwidth.SSL.Encrypted),
+			strconv.Itoa(a.Bandwidth.SSL.Unencrypted),
+			strconv.Itoa(a.Threats.All),
+			strconv.Itoa(a.Pageviews.All),
+			strconv.Itoa(a.Uniques.All),
+			strconv.FormatFloat(a.CacheHitRatio(), 'f', 4, 64),
+			strconv.FormatFloat(a.BandwidthSaved(), 'f', 4, 64),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteZoneAnalyticsJSONLines writes a timeseries as JSON Lines, one object
+// per period in the same form as the API returns it.
+func WriteZoneAnalyticsJSONLines(w io.Writer, timeseries []ZoneAnalytics) error {
+	enc := json.NewEncoder(w)
+	for _, a := range timeseries {
+		if err := enc.Encode(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package cloudflare
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testZoneAnalytics(since string, requests, cached int) ZoneAnalytics {
+	start, _ := time.Parse(time.RFC3339, since)
+	a := ZoneAnalytics{Since: start, Until: start.Add(time.Hour)}
+	a.Requests.All = requests
+	a.Requests.Cached = cached
+	a.Requests.Uncached = requests - cached
+	a.Requests.Country = map[string]int{"US": requests}
+	a.Bandwidth.All = requests * 1000
+	a.Bandwidth.Cached = cached * 1000
+	a.Uniques.All = 1
+	return a
+}
+
+func TestZoneAnalyticsLast(t *testing.T) {
+	opts := ZoneAnalyticsLast(24 * time.Hour)
+	if assert.NotNil(t, opts.Since) && assert.NotNil(t, opts.Until) {
+		assert.Equal(t, 24*time.Hour, opts.Until.Sub(*opts.Since))
+		assert.Equal(t, 0, opts.Until.Second())
+	}
+}
+
+func TestZoneAnalyticsRatios(t *testing.T) {
+	a := testZoneAnalytics("2018-08-01T00:00:00Z", 200, 150)
+	assert.Equal(t, 0.75, a.CacheHitRatio())
+	assert.Equal(t, 0.75, a.BandwidthSaved())
+	assert.Equal(t, 0.0, ZoneAnalytics{}.CacheHitRatio())
+	assert.Equal(t, 0.0, ZoneAnalytics{}.BandwidthSaved())
+}
+
+func TestResampleZoneAnalytics(t *testing.T) {
+	ts := []ZoneAnalytics{
+		testZoneAnalytics("2018-08-02T01:00:00Z", 10, 5),
+		testZoneAnalytics("2018-08-01T00:00:00Z", 100, 50),
+		testZoneAnalytics("2018-08-01T23:00:00Z", 20, 10),
+	}
+	days := ResampleZoneAnalytics(ts, 24*time.Hour)
+	if assert.Len(t, days, 2) {
+		assert.Equal(t, "2018-08-01T00:00:00Z", days[0].Since.Format(time.RFC3339))
+		assert.Equal(t, "2018-08-02T00:00:00Z", days[0].Until.Format(time.RFC3339))
+		assert.Equal(t, 120, days[0].Requests.All)
+		assert.Equal(t, map[string]int{"US": 120}, days[0].Requests.Country)
+		assert.Equal(t, 2, days[0].Uniques.All)
+		assert.Equal(t, 10, days[1].Requests.All)
+	}
+
+	total := SumZoneAnalytics(ts)
+	assert.Equal(t, 130, total.Requests.All)
+	assert.Equal(t, "2018-08-01T00:00:00Z", total.Since.Format(time.RFC3339))
+	assert.Equal(t, "2018-08-02T02:00:00Z", total.Until.Format(time.RFC3339))
+
+	// The inputs are not modified.
+	assert.Equal(t, map[string]int{"US": 100}, ts[1].Requests.Country)
+}
+
+func TestWriteZoneAnalyticsCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteZoneAnalyticsCSV(&buf, []ZoneAnalytics{testZoneAnalytics("2018-08-01T00:00:00Z", 4, 1)})
+	if assert.NoError(t, err) {
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Len(t, lines, 2)
+		assert.True(t, strings.HasPrefix(lines[0], "since,until,requests_all,"))
+		assert.Equal(t, "2018-08-01T00:00:00Z,2018-08-01T01:00:00Z,4,1,3,0,0,4000,1000,0,0,0,0,0,1,0.2500,0.2500", lines[1])
+	}
+}
+
+func TestWriteZoneAnalyticsJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	ts := []ZoneAnalytics{
+		testZoneAnalytics("2018-08-01T00:00:00Z", 4, 1),
+		testZoneAnalytics("2018-08-01T01:00:00Z", 2, 1),
+	}
+	err := WriteZoneAnalyticsJSONLines(&buf, ts)
+	if assert.NoError(t, err) {
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if assert.Len(t, lines, 2) {
+			assert.Contains(t, lines[0], `"since":"2018-08-01T00:00:00Z"`)
+			assert.Contains(t, lines[1], `"uniques":{"all":1}`)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package cloudflare
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"testing"
 	"time"
@@ -582,3 +584,46 @@ func TestZoneAnalyticsByColocation(t *testing.T) {
 	_, err = client.ZoneAnalyticsDashboard("bar", ZoneAnalyticsOptions{})
 	assert.Error(t, err)
 }
+
+func TestPurgeCacheRequestJSON(t *testing.T) {
+	pcr := PurgeCacheRequest{
+		Files: []string{"https://example.com/a"},
+		FilesWithHeaders: []PurgeFile{
+			{URL: "https://example.com/b", Headers: map[string]string{"CF-Device-Type": "mobile"}},
+		},
+	}
+	b, err := json.Marshal(pcr)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"files":["https://example.com/a",{"url":"https://example.com/b","headers":{"CF-Device-Type":"mobile"}}]}`, string(b))
+	}
+
+	var decoded PurgeCacheRequest
+	if assert.NoError(t, json.Unmarshal(b, &decoded)) {
+		assert.Equal(t, pcr, decoded)
+	}
+
+	b, err = json.Marshal(PurgeCacheRequest{Everything: true})
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"purge_everything":true}`, string(b))
+	}
+}
+
+func TestPurgeCache(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/abc/purge_cache", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		b, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `{"files":[{"url":"https://example.com/","headers":{"Accept-Language":"fr"}}]}`, string(b))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"id":"abc"}}`)
+	})
+
+	res, err := client.PurgeCache("abc", PurgeCacheRequest{FilesWithHeaders: []PurgeFile{
+		{URL: "https://example.com/", Headers: map[string]string{"Accept-Language": "fr"}},
+	}})
+	if assert.NoError(t, err) {
+		assert.True(t, res.Success)
+	}
+}